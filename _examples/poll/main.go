@@ -27,8 +27,10 @@ import (
 	"golang.org/x/crypto/ssh/terminal"
 
 	"bufio"
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
 	"syscall"
 	"time"
@@ -54,22 +56,44 @@ func main() {
 	fmt.Print("\n")
 
 	fmt.Print("Polling for new reports and activity:\n")
-	errChan, reportsChan, activitiesChan := polling.Start(
+	poller := polling.NewPoller(
 		h1.NewClient(tp.Client()),
 		h1.ReportListFilter{
 			Program: []string{strings.TrimSpace(program)},
 		},
-		time.Second*20,
-		time.Second*60,
+		&polling.Options{
+			Interval:        time.Second * 20,
+			Window:          time.Second * 60,
+			BufferSize:      100,
+			ContinueOnError: true,
+		},
 	)
 
-	for {
+	// Stop polling on Ctrl+C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go poller.Run(ctx)
+
+	errors, reports, activities := poller.Errors(), poller.Reports(), poller.Activities()
+	for errors != nil || reports != nil || activities != nil {
 		select {
-		case err := <-errChan:
-			panic(err)
-		case report := <-reportsChan:
+		case err, ok := <-errors:
+			if !ok {
+				errors = nil
+				continue
+			}
+			fmt.Printf("Error: %v\n", err)
+		case report, ok := <-reports:
+			if !ok {
+				reports = nil
+				continue
+			}
 			fmt.Printf("New Report [%s]: %s\n", *report.ID, *report.Title)
-		case activity := <-activitiesChan:
+		case activity, ok := <-activities:
+			if !ok {
+				activities = nil
+				continue
+			}
 			fmt.Printf("New Activity [%s/%s/%s]: %s\n", *activity.Report().ID, *activity.ID, *activity.Type, *activity.Message)
 		}
 	}
@@ -0,0 +1,809 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+// ActivityDetail is the typed detail of an activity returned by Activity.ParseActivity. It is implemented by
+// every Activity* struct of this package, and by *ActivityUnknown for activity types which are not known yet.
+type ActivityDetail interface {
+	// Kind returns the activity type of the detail, such as ActivityCommentType
+	Kind() string
+
+	// activityDetail prevents types outside of this package from implementing ActivityDetail
+	activityDetail()
+}
+
+// Kind returns ActivityAgreedOnGoingPublicType
+func (*ActivityAgreedOnGoingPublic) Kind() string {
+	return ActivityAgreedOnGoingPublicType
+}
+
+func (*ActivityAgreedOnGoingPublic) activityDetail() {}
+
+// Kind returns ActivityBountyAwardedType
+func (*ActivityBountyAwarded) Kind() string {
+	return ActivityBountyAwardedType
+}
+
+func (*ActivityBountyAwarded) activityDetail() {}
+
+// Kind returns ActivityBountySuggestedType
+func (*ActivityBountySuggested) Kind() string {
+	return ActivityBountySuggestedType
+}
+
+func (*ActivityBountySuggested) activityDetail() {}
+
+// Kind returns ActivityBugClonedType
+func (*ActivityBugCloned) Kind() string {
+	return ActivityBugClonedType
+}
+
+func (*ActivityBugCloned) activityDetail() {}
+
+// Kind returns ActivityBugDuplicateType
+func (*ActivityBugDuplicate) Kind() string {
+	return ActivityBugDuplicateType
+}
+
+func (*ActivityBugDuplicate) activityDetail() {}
+
+// Kind returns ActivityBugFiledType
+func (*ActivityBugFiled) Kind() string {
+	return ActivityBugFiledType
+}
+
+func (*ActivityBugFiled) activityDetail() {}
+
+// Kind returns ActivityBugInactiveType
+func (*ActivityBugInactive) Kind() string {
+	return ActivityBugInactiveType
+}
+
+func (*ActivityBugInactive) activityDetail() {}
+
+// Kind returns ActivityBugInformativeType
+func (*ActivityBugInformative) Kind() string {
+	return ActivityBugInformativeType
+}
+
+func (*ActivityBugInformative) activityDetail() {}
+
+// Kind returns ActivityBugNeedsMoreInfoType
+func (*ActivityBugNeedsMoreInfo) Kind() string {
+	return ActivityBugNeedsMoreInfoType
+}
+
+func (*ActivityBugNeedsMoreInfo) activityDetail() {}
+
+// Kind returns ActivityBugNewType
+func (*ActivityBugNew) Kind() string {
+	return ActivityBugNewType
+}
+
+func (*ActivityBugNew) activityDetail() {}
+
+// Kind returns ActivityBugNotApplicableType
+func (*ActivityBugNotApplicable) Kind() string {
+	return ActivityBugNotApplicableType
+}
+
+func (*ActivityBugNotApplicable) activityDetail() {}
+
+// Kind returns ActivityBugReopenedType
+func (*ActivityBugReopened) Kind() string {
+	return ActivityBugReopenedType
+}
+
+func (*ActivityBugReopened) activityDetail() {}
+
+// Kind returns ActivityBugResolvedType
+func (*ActivityBugResolved) Kind() string {
+	return ActivityBugResolvedType
+}
+
+func (*ActivityBugResolved) activityDetail() {}
+
+// Kind returns ActivityBugRetestingType
+func (*ActivityBugRetesting) Kind() string {
+	return ActivityBugRetestingType
+}
+
+func (*ActivityBugRetesting) activityDetail() {}
+
+// Kind returns ActivityBugSpamType
+func (*ActivityBugSpam) Kind() string {
+	return ActivityBugSpamType
+}
+
+func (*ActivityBugSpam) activityDetail() {}
+
+// Kind returns ActivityBugTriagedType
+func (*ActivityBugTriaged) Kind() string {
+	return ActivityBugTriagedType
+}
+
+func (*ActivityBugTriaged) activityDetail() {}
+
+// Kind returns ActivityCancelledDisclosureRequestType
+func (*ActivityCancelledDisclosureRequest) Kind() string {
+	return ActivityCancelledDisclosureRequestType
+}
+
+func (*ActivityCancelledDisclosureRequest) activityDetail() {}
+
+// Kind returns ActivityChangedScopeType
+func (*ActivityChangedScope) Kind() string {
+	return ActivityChangedScopeType
+}
+
+func (*ActivityChangedScope) activityDetail() {}
+
+// Kind returns ActivityCommentType
+func (*ActivityComment) Kind() string {
+	return ActivityCommentType
+}
+
+func (*ActivityComment) activityDetail() {}
+
+// Kind returns ActivityCommentsClosedType
+func (*ActivityCommentsClosed) Kind() string {
+	return ActivityCommentsClosedType
+}
+
+func (*ActivityCommentsClosed) activityDetail() {}
+
+// Kind returns ActivityCommentsOpenedType
+func (*ActivityCommentsOpened) Kind() string {
+	return ActivityCommentsOpenedType
+}
+
+func (*ActivityCommentsOpened) activityDetail() {}
+
+// Kind returns ActivityCVEIDAddedType
+func (*ActivityCVEIDAdded) Kind() string {
+	return ActivityCVEIDAddedType
+}
+
+func (*ActivityCVEIDAdded) activityDetail() {}
+
+// Kind returns ActivityExternalAdvisoryAddedType
+func (*ActivityExternalAdvisoryAdded) Kind() string {
+	return ActivityExternalAdvisoryAddedType
+}
+
+func (*ActivityExternalAdvisoryAdded) activityDetail() {}
+
+// Kind returns ActivityExternalUserInvitationCancelledType
+func (*ActivityExternalUserInvitationCancelled) Kind() string {
+	return ActivityExternalUserInvitationCancelledType
+}
+
+func (*ActivityExternalUserInvitationCancelled) activityDetail() {}
+
+// Kind returns ActivityExternalUserInvitedType
+func (*ActivityExternalUserInvited) Kind() string {
+	return ActivityExternalUserInvitedType
+}
+
+func (*ActivityExternalUserInvited) activityDetail() {}
+
+// Kind returns ActivityExternalUserJoinedType
+func (*ActivityExternalUserJoined) Kind() string {
+	return ActivityExternalUserJoinedType
+}
+
+func (*ActivityExternalUserJoined) activityDetail() {}
+
+// Kind returns ActivityExternalUserRemovedType
+func (*ActivityExternalUserRemoved) Kind() string {
+	return ActivityExternalUserRemovedType
+}
+
+func (*ActivityExternalUserRemoved) activityDetail() {}
+
+// Kind returns ActivityGroupAssignedToBugType
+func (*ActivityGroupAssignedToBug) Kind() string {
+	return ActivityGroupAssignedToBugType
+}
+
+func (*ActivityGroupAssignedToBug) activityDetail() {}
+
+// Kind returns ActivityHackerRequestedMediationType
+func (*ActivityHackerRequestedMediation) Kind() string {
+	return ActivityHackerRequestedMediationType
+}
+
+func (*ActivityHackerRequestedMediation) activityDetail() {}
+
+// Kind returns ActivityManuallyDisclosedType
+func (*ActivityManuallyDisclosed) Kind() string {
+	return ActivityManuallyDisclosedType
+}
+
+func (*ActivityManuallyDisclosed) activityDetail() {}
+
+// Kind returns ActivityMediationRequestedType
+func (*ActivityMediationRequested) Kind() string {
+	return ActivityMediationRequestedType
+}
+
+func (*ActivityMediationRequested) activityDetail() {}
+
+// Kind returns ActivityNobodyAssignedToBugType
+func (*ActivityNobodyAssignedToBug) Kind() string {
+	return ActivityNobodyAssignedToBugType
+}
+
+func (*ActivityNobodyAssignedToBug) activityDetail() {}
+
+// Kind returns ActivityNotEligibleForBountyType
+func (*ActivityNotEligibleForBounty) Kind() string {
+	return ActivityNotEligibleForBountyType
+}
+
+func (*ActivityNotEligibleForBounty) activityDetail() {}
+
+// Kind returns ActivityProgramInactiveType
+func (*ActivityProgramInactive) Kind() string {
+	return ActivityProgramInactiveType
+}
+
+func (*ActivityProgramInactive) activityDetail() {}
+
+// Kind returns ActivityReferenceIDAddedType
+func (*ActivityReferenceIDAdded) Kind() string {
+	return ActivityReferenceIDAddedType
+}
+
+func (*ActivityReferenceIDAdded) activityDetail() {}
+
+// Kind returns ActivityReportBecamePublicType
+func (*ActivityReportBecamePublic) Kind() string {
+	return ActivityReportBecamePublicType
+}
+
+func (*ActivityReportBecamePublic) activityDetail() {}
+
+// Kind returns ActivityReportCollaboratorInvitedType
+func (*ActivityReportCollaboratorInvited) Kind() string {
+	return ActivityReportCollaboratorInvitedType
+}
+
+func (*ActivityReportCollaboratorInvited) activityDetail() {}
+
+// Kind returns ActivityReportCollaboratorJoinedType
+func (*ActivityReportCollaboratorJoined) Kind() string {
+	return ActivityReportCollaboratorJoinedType
+}
+
+func (*ActivityReportCollaboratorJoined) activityDetail() {}
+
+// Kind returns ActivityReportRetestApprovedType
+func (*ActivityReportRetestApproved) Kind() string {
+	return ActivityReportRetestApprovedType
+}
+
+func (*ActivityReportRetestApproved) activityDetail() {}
+
+// Kind returns ActivityReportRetestRejectedType
+func (*ActivityReportRetestRejected) Kind() string {
+	return ActivityReportRetestRejectedType
+}
+
+func (*ActivityReportRetestRejected) activityDetail() {}
+
+// Kind returns ActivityReportSeverityUpdatedType
+func (*ActivityReportSeverityUpdated) Kind() string {
+	return ActivityReportSeverityUpdatedType
+}
+
+func (*ActivityReportSeverityUpdated) activityDetail() {}
+
+// Kind returns ActivityReportTitleUpdatedType
+func (*ActivityReportTitleUpdated) Kind() string {
+	return ActivityReportTitleUpdatedType
+}
+
+func (*ActivityReportTitleUpdated) activityDetail() {}
+
+// Kind returns ActivityReportVulnerabilityTypesUpdatedType
+func (*ActivityReportVulnerabilityTypesUpdated) Kind() string {
+	return ActivityReportVulnerabilityTypesUpdatedType
+}
+
+func (*ActivityReportVulnerabilityTypesUpdated) activityDetail() {}
+
+// Kind returns ActivityRetestUserExpiredType
+func (*ActivityRetestUserExpired) Kind() string {
+	return ActivityRetestUserExpiredType
+}
+
+func (*ActivityRetestUserExpired) activityDetail() {}
+
+// Kind returns ActivitySwagAwardedType
+func (*ActivitySwagAwarded) Kind() string {
+	return ActivitySwagAwardedType
+}
+
+func (*ActivitySwagAwarded) activityDetail() {}
+
+// Kind returns ActivityUserAssignedToBugType
+func (*ActivityUserAssignedToBug) Kind() string {
+	return ActivityUserAssignedToBugType
+}
+
+func (*ActivityUserAssignedToBug) activityDetail() {}
+
+// Kind returns ActivityUserBannedFromProgramType
+func (*ActivityUserBannedFromProgram) Kind() string {
+	return ActivityUserBannedFromProgramType
+}
+
+func (*ActivityUserBannedFromProgram) activityDetail() {}
+
+// Kind returns ActivityUserCompletedRetestType
+func (*ActivityUserCompletedRetest) Kind() string {
+	return ActivityUserCompletedRetestType
+}
+
+func (*ActivityUserCompletedRetest) activityDetail() {}
+
+// Kind returns ActivityUserLeftRetestType
+func (*ActivityUserLeftRetest) Kind() string {
+	return ActivityUserLeftRetestType
+}
+
+func (*ActivityUserLeftRetest) activityDetail() {}
+
+// Kind returns the type of the activity
+func (a *ActivityUnknown) Kind() string {
+	if a.Type == nil {
+		return ""
+	}
+	return *a.Type
+}
+
+func (*ActivityUnknown) activityDetail() {}
+
+// ActivityVisitor handles each activity type in its own method. It is used with Activity.Accept.
+//
+// Implementing ActivityVisitor directly makes the compiler report every activity type which gains support
+// in this package and is not handled yet. Embed DefaultActivityVisitor to only handle some of them instead.
+type ActivityVisitor interface {
+	VisitAgreedOnGoingPublic(activity *Activity, detail *ActivityAgreedOnGoingPublic) error
+	VisitBountyAwarded(activity *Activity, detail *ActivityBountyAwarded) error
+	VisitBountySuggested(activity *Activity, detail *ActivityBountySuggested) error
+	VisitBugCloned(activity *Activity, detail *ActivityBugCloned) error
+	VisitBugDuplicate(activity *Activity, detail *ActivityBugDuplicate) error
+	VisitBugFiled(activity *Activity, detail *ActivityBugFiled) error
+	VisitBugInactive(activity *Activity, detail *ActivityBugInactive) error
+	VisitBugInformative(activity *Activity, detail *ActivityBugInformative) error
+	VisitBugNeedsMoreInfo(activity *Activity, detail *ActivityBugNeedsMoreInfo) error
+	VisitBugNew(activity *Activity, detail *ActivityBugNew) error
+	VisitBugNotApplicable(activity *Activity, detail *ActivityBugNotApplicable) error
+	VisitBugReopened(activity *Activity, detail *ActivityBugReopened) error
+	VisitBugResolved(activity *Activity, detail *ActivityBugResolved) error
+	VisitBugRetesting(activity *Activity, detail *ActivityBugRetesting) error
+	VisitBugSpam(activity *Activity, detail *ActivityBugSpam) error
+	VisitBugTriaged(activity *Activity, detail *ActivityBugTriaged) error
+	VisitCancelledDisclosureRequest(activity *Activity, detail *ActivityCancelledDisclosureRequest) error
+	VisitChangedScope(activity *Activity, detail *ActivityChangedScope) error
+	VisitComment(activity *Activity, detail *ActivityComment) error
+	VisitCommentsClosed(activity *Activity, detail *ActivityCommentsClosed) error
+	VisitCommentsOpened(activity *Activity, detail *ActivityCommentsOpened) error
+	VisitCVEIDAdded(activity *Activity, detail *ActivityCVEIDAdded) error
+	VisitExternalAdvisoryAdded(activity *Activity, detail *ActivityExternalAdvisoryAdded) error
+	VisitExternalUserInvitationCancelled(activity *Activity, detail *ActivityExternalUserInvitationCancelled) error
+	VisitExternalUserInvited(activity *Activity, detail *ActivityExternalUserInvited) error
+	VisitExternalUserJoined(activity *Activity, detail *ActivityExternalUserJoined) error
+	VisitExternalUserRemoved(activity *Activity, detail *ActivityExternalUserRemoved) error
+	VisitGroupAssignedToBug(activity *Activity, detail *ActivityGroupAssignedToBug) error
+	VisitHackerRequestedMediation(activity *Activity, detail *ActivityHackerRequestedMediation) error
+	VisitManuallyDisclosed(activity *Activity, detail *ActivityManuallyDisclosed) error
+	VisitMediationRequested(activity *Activity, detail *ActivityMediationRequested) error
+	VisitNobodyAssignedToBug(activity *Activity, detail *ActivityNobodyAssignedToBug) error
+	VisitNotEligibleForBounty(activity *Activity, detail *ActivityNotEligibleForBounty) error
+	VisitProgramInactive(activity *Activity, detail *ActivityProgramInactive) error
+	VisitReferenceIDAdded(activity *Activity, detail *ActivityReferenceIDAdded) error
+	VisitReportBecamePublic(activity *Activity, detail *ActivityReportBecamePublic) error
+	VisitReportCollaboratorInvited(activity *Activity, detail *ActivityReportCollaboratorInvited) error
+	VisitReportCollaboratorJoined(activity *Activity, detail *ActivityReportCollaboratorJoined) error
+	VisitReportRetestApproved(activity *Activity, detail *ActivityReportRetestApproved) error
+	VisitReportRetestRejected(activity *Activity, detail *ActivityReportRetestRejected) error
+	VisitReportSeverityUpdated(activity *Activity, detail *ActivityReportSeverityUpdated) error
+	VisitReportTitleUpdated(activity *Activity, detail *ActivityReportTitleUpdated) error
+	VisitReportVulnerabilityTypesUpdated(activity *Activity, detail *ActivityReportVulnerabilityTypesUpdated) error
+	VisitRetestUserExpired(activity *Activity, detail *ActivityRetestUserExpired) error
+	VisitSwagAwarded(activity *Activity, detail *ActivitySwagAwarded) error
+	VisitUserAssignedToBug(activity *Activity, detail *ActivityUserAssignedToBug) error
+	VisitUserBannedFromProgram(activity *Activity, detail *ActivityUserBannedFromProgram) error
+	VisitUserCompletedRetest(activity *Activity, detail *ActivityUserCompletedRetest) error
+	VisitUserLeftRetest(activity *Activity, detail *ActivityUserLeftRetest) error
+
+	// VisitDefault is called for activity types which are not known to this package
+	VisitDefault(activity *Activity, detail ActivityDetail) error
+}
+
+// DefaultActivityVisitor implements every method of ActivityVisitor by ignoring the activity.
+// It is meant to be embedded in visitors which only handle some activity types.
+type DefaultActivityVisitor struct{}
+
+var _ ActivityVisitor = DefaultActivityVisitor{}
+
+// VisitAgreedOnGoingPublic ignores the activity
+func (DefaultActivityVisitor) VisitAgreedOnGoingPublic(*Activity, *ActivityAgreedOnGoingPublic) error {
+	return nil
+}
+
+// VisitBountyAwarded ignores the activity
+func (DefaultActivityVisitor) VisitBountyAwarded(*Activity, *ActivityBountyAwarded) error {
+	return nil
+}
+
+// VisitBountySuggested ignores the activity
+func (DefaultActivityVisitor) VisitBountySuggested(*Activity, *ActivityBountySuggested) error {
+	return nil
+}
+
+// VisitBugCloned ignores the activity
+func (DefaultActivityVisitor) VisitBugCloned(*Activity, *ActivityBugCloned) error {
+	return nil
+}
+
+// VisitBugDuplicate ignores the activity
+func (DefaultActivityVisitor) VisitBugDuplicate(*Activity, *ActivityBugDuplicate) error {
+	return nil
+}
+
+// VisitBugFiled ignores the activity
+func (DefaultActivityVisitor) VisitBugFiled(*Activity, *ActivityBugFiled) error {
+	return nil
+}
+
+// VisitBugInactive ignores the activity
+func (DefaultActivityVisitor) VisitBugInactive(*Activity, *ActivityBugInactive) error {
+	return nil
+}
+
+// VisitBugInformative ignores the activity
+func (DefaultActivityVisitor) VisitBugInformative(*Activity, *ActivityBugInformative) error {
+	return nil
+}
+
+// VisitBugNeedsMoreInfo ignores the activity
+func (DefaultActivityVisitor) VisitBugNeedsMoreInfo(*Activity, *ActivityBugNeedsMoreInfo) error {
+	return nil
+}
+
+// VisitBugNew ignores the activity
+func (DefaultActivityVisitor) VisitBugNew(*Activity, *ActivityBugNew) error {
+	return nil
+}
+
+// VisitBugNotApplicable ignores the activity
+func (DefaultActivityVisitor) VisitBugNotApplicable(*Activity, *ActivityBugNotApplicable) error {
+	return nil
+}
+
+// VisitBugReopened ignores the activity
+func (DefaultActivityVisitor) VisitBugReopened(*Activity, *ActivityBugReopened) error {
+	return nil
+}
+
+// VisitBugResolved ignores the activity
+func (DefaultActivityVisitor) VisitBugResolved(*Activity, *ActivityBugResolved) error {
+	return nil
+}
+
+// VisitBugRetesting ignores the activity
+func (DefaultActivityVisitor) VisitBugRetesting(*Activity, *ActivityBugRetesting) error {
+	return nil
+}
+
+// VisitBugSpam ignores the activity
+func (DefaultActivityVisitor) VisitBugSpam(*Activity, *ActivityBugSpam) error {
+	return nil
+}
+
+// VisitBugTriaged ignores the activity
+func (DefaultActivityVisitor) VisitBugTriaged(*Activity, *ActivityBugTriaged) error {
+	return nil
+}
+
+// VisitCancelledDisclosureRequest ignores the activity
+func (DefaultActivityVisitor) VisitCancelledDisclosureRequest(*Activity, *ActivityCancelledDisclosureRequest) error {
+	return nil
+}
+
+// VisitChangedScope ignores the activity
+func (DefaultActivityVisitor) VisitChangedScope(*Activity, *ActivityChangedScope) error {
+	return nil
+}
+
+// VisitComment ignores the activity
+func (DefaultActivityVisitor) VisitComment(*Activity, *ActivityComment) error {
+	return nil
+}
+
+// VisitCommentsClosed ignores the activity
+func (DefaultActivityVisitor) VisitCommentsClosed(*Activity, *ActivityCommentsClosed) error {
+	return nil
+}
+
+// VisitCommentsOpened ignores the activity
+func (DefaultActivityVisitor) VisitCommentsOpened(*Activity, *ActivityCommentsOpened) error {
+	return nil
+}
+
+// VisitCVEIDAdded ignores the activity
+func (DefaultActivityVisitor) VisitCVEIDAdded(*Activity, *ActivityCVEIDAdded) error {
+	return nil
+}
+
+// VisitExternalAdvisoryAdded ignores the activity
+func (DefaultActivityVisitor) VisitExternalAdvisoryAdded(*Activity, *ActivityExternalAdvisoryAdded) error {
+	return nil
+}
+
+// VisitExternalUserInvitationCancelled ignores the activity
+func (DefaultActivityVisitor) VisitExternalUserInvitationCancelled(*Activity, *ActivityExternalUserInvitationCancelled) error {
+	return nil
+}
+
+// VisitExternalUserInvited ignores the activity
+func (DefaultActivityVisitor) VisitExternalUserInvited(*Activity, *ActivityExternalUserInvited) error {
+	return nil
+}
+
+// VisitExternalUserJoined ignores the activity
+func (DefaultActivityVisitor) VisitExternalUserJoined(*Activity, *ActivityExternalUserJoined) error {
+	return nil
+}
+
+// VisitExternalUserRemoved ignores the activity
+func (DefaultActivityVisitor) VisitExternalUserRemoved(*Activity, *ActivityExternalUserRemoved) error {
+	return nil
+}
+
+// VisitGroupAssignedToBug ignores the activity
+func (DefaultActivityVisitor) VisitGroupAssignedToBug(*Activity, *ActivityGroupAssignedToBug) error {
+	return nil
+}
+
+// VisitHackerRequestedMediation ignores the activity
+func (DefaultActivityVisitor) VisitHackerRequestedMediation(*Activity, *ActivityHackerRequestedMediation) error {
+	return nil
+}
+
+// VisitManuallyDisclosed ignores the activity
+func (DefaultActivityVisitor) VisitManuallyDisclosed(*Activity, *ActivityManuallyDisclosed) error {
+	return nil
+}
+
+// VisitMediationRequested ignores the activity
+func (DefaultActivityVisitor) VisitMediationRequested(*Activity, *ActivityMediationRequested) error {
+	return nil
+}
+
+// VisitNobodyAssignedToBug ignores the activity
+func (DefaultActivityVisitor) VisitNobodyAssignedToBug(*Activity, *ActivityNobodyAssignedToBug) error {
+	return nil
+}
+
+// VisitNotEligibleForBounty ignores the activity
+func (DefaultActivityVisitor) VisitNotEligibleForBounty(*Activity, *ActivityNotEligibleForBounty) error {
+	return nil
+}
+
+// VisitProgramInactive ignores the activity
+func (DefaultActivityVisitor) VisitProgramInactive(*Activity, *ActivityProgramInactive) error {
+	return nil
+}
+
+// VisitReferenceIDAdded ignores the activity
+func (DefaultActivityVisitor) VisitReferenceIDAdded(*Activity, *ActivityReferenceIDAdded) error {
+	return nil
+}
+
+// VisitReportBecamePublic ignores the activity
+func (DefaultActivityVisitor) VisitReportBecamePublic(*Activity, *ActivityReportBecamePublic) error {
+	return nil
+}
+
+// VisitReportCollaboratorInvited ignores the activity
+func (DefaultActivityVisitor) VisitReportCollaboratorInvited(*Activity, *ActivityReportCollaboratorInvited) error {
+	return nil
+}
+
+// VisitReportCollaboratorJoined ignores the activity
+func (DefaultActivityVisitor) VisitReportCollaboratorJoined(*Activity, *ActivityReportCollaboratorJoined) error {
+	return nil
+}
+
+// VisitReportRetestApproved ignores the activity
+func (DefaultActivityVisitor) VisitReportRetestApproved(*Activity, *ActivityReportRetestApproved) error {
+	return nil
+}
+
+// VisitReportRetestRejected ignores the activity
+func (DefaultActivityVisitor) VisitReportRetestRejected(*Activity, *ActivityReportRetestRejected) error {
+	return nil
+}
+
+// VisitReportSeverityUpdated ignores the activity
+func (DefaultActivityVisitor) VisitReportSeverityUpdated(*Activity, *ActivityReportSeverityUpdated) error {
+	return nil
+}
+
+// VisitReportTitleUpdated ignores the activity
+func (DefaultActivityVisitor) VisitReportTitleUpdated(*Activity, *ActivityReportTitleUpdated) error {
+	return nil
+}
+
+// VisitReportVulnerabilityTypesUpdated ignores the activity
+func (DefaultActivityVisitor) VisitReportVulnerabilityTypesUpdated(*Activity, *ActivityReportVulnerabilityTypesUpdated) error {
+	return nil
+}
+
+// VisitRetestUserExpired ignores the activity
+func (DefaultActivityVisitor) VisitRetestUserExpired(*Activity, *ActivityRetestUserExpired) error {
+	return nil
+}
+
+// VisitSwagAwarded ignores the activity
+func (DefaultActivityVisitor) VisitSwagAwarded(*Activity, *ActivitySwagAwarded) error {
+	return nil
+}
+
+// VisitUserAssignedToBug ignores the activity
+func (DefaultActivityVisitor) VisitUserAssignedToBug(*Activity, *ActivityUserAssignedToBug) error {
+	return nil
+}
+
+// VisitUserBannedFromProgram ignores the activity
+func (DefaultActivityVisitor) VisitUserBannedFromProgram(*Activity, *ActivityUserBannedFromProgram) error {
+	return nil
+}
+
+// VisitUserCompletedRetest ignores the activity
+func (DefaultActivityVisitor) VisitUserCompletedRetest(*Activity, *ActivityUserCompletedRetest) error {
+	return nil
+}
+
+// VisitUserLeftRetest ignores the activity
+func (DefaultActivityVisitor) VisitUserLeftRetest(*Activity, *ActivityUserLeftRetest) error {
+	return nil
+}
+
+// VisitDefault ignores the activity
+func (DefaultActivityVisitor) VisitDefault(*Activity, ActivityDetail) error {
+	return nil
+}
+
+// Accept parses the activity and calls the method of v matching its type. Errors from parsing the activity
+// and from v are returned.
+func (a *Activity) Accept(v ActivityVisitor) error {
+	detail, err := a.ParseActivity()
+	if err != nil {
+		return err
+	}
+	switch detail := detail.(type) {
+	case *ActivityAgreedOnGoingPublic:
+		return v.VisitAgreedOnGoingPublic(a, detail)
+	case *ActivityBountyAwarded:
+		return v.VisitBountyAwarded(a, detail)
+	case *ActivityBountySuggested:
+		return v.VisitBountySuggested(a, detail)
+	case *ActivityBugCloned:
+		return v.VisitBugCloned(a, detail)
+	case *ActivityBugDuplicate:
+		return v.VisitBugDuplicate(a, detail)
+	case *ActivityBugFiled:
+		return v.VisitBugFiled(a, detail)
+	case *ActivityBugInactive:
+		return v.VisitBugInactive(a, detail)
+	case *ActivityBugInformative:
+		return v.VisitBugInformative(a, detail)
+	case *ActivityBugNeedsMoreInfo:
+		return v.VisitBugNeedsMoreInfo(a, detail)
+	case *ActivityBugNew:
+		return v.VisitBugNew(a, detail)
+	case *ActivityBugNotApplicable:
+		return v.VisitBugNotApplicable(a, detail)
+	case *ActivityBugReopened:
+		return v.VisitBugReopened(a, detail)
+	case *ActivityBugResolved:
+		return v.VisitBugResolved(a, detail)
+	case *ActivityBugRetesting:
+		return v.VisitBugRetesting(a, detail)
+	case *ActivityBugSpam:
+		return v.VisitBugSpam(a, detail)
+	case *ActivityBugTriaged:
+		return v.VisitBugTriaged(a, detail)
+	case *ActivityCancelledDisclosureRequest:
+		return v.VisitCancelledDisclosureRequest(a, detail)
+	case *ActivityChangedScope:
+		return v.VisitChangedScope(a, detail)
+	case *ActivityComment:
+		return v.VisitComment(a, detail)
+	case *ActivityCommentsClosed:
+		return v.VisitCommentsClosed(a, detail)
+	case *ActivityCommentsOpened:
+		return v.VisitCommentsOpened(a, detail)
+	case *ActivityCVEIDAdded:
+		return v.VisitCVEIDAdded(a, detail)
+	case *ActivityExternalAdvisoryAdded:
+		return v.VisitExternalAdvisoryAdded(a, detail)
+	case *ActivityExternalUserInvitationCancelled:
+		return v.VisitExternalUserInvitationCancelled(a, detail)
+	case *ActivityExternalUserInvited:
+		return v.VisitExternalUserInvited(a, detail)
+	case *ActivityExternalUserJoined:
+		return v.VisitExternalUserJoined(a, detail)
+	case *ActivityExternalUserRemoved:
+		return v.VisitExternalUserRemoved(a, detail)
+	case *ActivityGroupAssignedToBug:
+		return v.VisitGroupAssignedToBug(a, detail)
+	case *ActivityHackerRequestedMediation:
+		return v.VisitHackerRequestedMediation(a, detail)
+	case *ActivityManuallyDisclosed:
+		return v.VisitManuallyDisclosed(a, detail)
+	case *ActivityMediationRequested:
+		return v.VisitMediationRequested(a, detail)
+	case *ActivityNobodyAssignedToBug:
+		return v.VisitNobodyAssignedToBug(a, detail)
+	case *ActivityNotEligibleForBounty:
+		return v.VisitNotEligibleForBounty(a, detail)
+	case *ActivityProgramInactive:
+		return v.VisitProgramInactive(a, detail)
+	case *ActivityReferenceIDAdded:
+		return v.VisitReferenceIDAdded(a, detail)
+	case *ActivityReportBecamePublic:
+		return v.VisitReportBecamePublic(a, detail)
+	case *ActivityReportCollaboratorInvited:
+		return v.VisitReportCollaboratorInvited(a, detail)
+	case *ActivityReportCollaboratorJoined:
+		return v.VisitReportCollaboratorJoined(a, detail)
+	case *ActivityReportRetestApproved:
+		return v.VisitReportRetestApproved(a, detail)
+	case *ActivityReportRetestRejected:
+		return v.VisitReportRetestRejected(a, detail)
+	case *ActivityReportSeverityUpdated:
+		return v.VisitReportSeverityUpdated(a, detail)
+	case *ActivityReportTitleUpdated:
+		return v.VisitReportTitleUpdated(a, detail)
+	case *ActivityReportVulnerabilityTypesUpdated:
+		return v.VisitReportVulnerabilityTypesUpdated(a, detail)
+	case *ActivityRetestUserExpired:
+		return v.VisitRetestUserExpired(a, detail)
+	case *ActivitySwagAwarded:
+		return v.VisitSwagAwarded(a, detail)
+	case *ActivityUserAssignedToBug:
+		return v.VisitUserAssignedToBug(a, detail)
+	case *ActivityUserBannedFromProgram:
+		return v.VisitUserBannedFromProgram(a, detail)
+	case *ActivityUserCompletedRetest:
+		return v.VisitUserCompletedRetest(a, detail)
+	case *ActivityUserLeftRetest:
+		return v.VisitUserLeftRetest(a, detail)
+	}
+	return v.VisitDefault(a, detail)
+}
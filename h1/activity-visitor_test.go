@@ -0,0 +1,95 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func Test_ActivityDetail_Kind(t *testing.T) {
+	fixtures, err := filepath.Glob("tests/resources/activity-*.json")
+	require.Nil(t, err)
+	require.NotEmpty(t, fixtures)
+	for _, fixture := range fixtures {
+		var actual Activity
+		loadResource(t, &actual, fixture)
+		detail, err := actual.ParseActivity()
+		require.Nil(t, err, fixture)
+		_, unknown := detail.(*ActivityUnknown)
+		assert.False(t, unknown, fixture)
+		assert.Equal(t, *actual.Type, detail.Kind(), fixture)
+	}
+
+	unknown := &ActivityUnknown{Resource{Type: String("activity-unknown")}}
+	assert.Equal(t, "activity-unknown", unknown.Kind())
+	assert.Equal(t, "", (&ActivityUnknown{}).Kind())
+}
+
+type recordingVisitor struct {
+	DefaultActivityVisitor
+	visited []string
+	err     error
+}
+
+func (v *recordingVisitor) VisitComment(a *Activity, detail *ActivityComment) error {
+	v.visited = append(v.visited, "comment:"+*a.ID)
+	return v.err
+}
+
+func (v *recordingVisitor) VisitDefault(a *Activity, detail ActivityDetail) error {
+	v.visited = append(v.visited, "default:"+detail.Kind())
+	return v.err
+}
+
+func Test_Activity_Accept(t *testing.T) {
+	var comment, triaged Activity
+	loadResource(t, &comment, "tests/resources/activity-comment.json")
+	loadResource(t, &triaged, "tests/resources/activity-bug-triaged.json")
+	unknown := Activity{
+		Type:    String("activity-unknown"),
+		rawData: []byte(`{"id":"1337","type":"activity-unknown"}`),
+	}
+
+	// Verify that activities are dispatched on their type
+	v := &recordingVisitor{}
+	for _, activity := range []*Activity{&comment, &triaged, &unknown} {
+		assert.Nil(t, activity.Accept(v))
+	}
+	assert.Equal(t, []string{"comment:1337", "default:activity-unknown"}, v.visited)
+
+	// Verify that errors of the visitor are returned
+	v = &recordingVisitor{err: errors.New("failed")}
+	assert.Equal(t, v.err, comment.Accept(v))
+
+	// Verify that malformed activities are not visited
+	v = &recordingVisitor{}
+	malformed := Activity{
+		Type:    String(ActivityBugDuplicateType),
+		rawData: []byte(`{"attributes":123}`),
+	}
+	assert.NotNil(t, malformed.Accept(v))
+	assert.Empty(t, v.visited)
+}
@@ -22,6 +22,7 @@ package h1
 
 import (
 	"encoding/json"
+	"fmt"
 )
 
 // Activity represents activities that have occured in a given report.
@@ -66,36 +67,98 @@ func (a *Activity) UnmarshalJSON(b []byte) error {
 	a.Attachments = helper.Relationships.Attachments.Data
 	a.RawActor = helper.Relationships.RawActor.Data
 	a.rawData = b
+	// Resolve the actor eagerly so a malformed relationship surfaces here
+	if _, err := parseActor(a.RawActor); err != nil {
+		return fmt.Errorf("h1: malformed actor of activity %s: %w", stringValue(a.ID), err)
+	}
 	return nil
 }
 
-// Actor returns returns the parsed actor. For recognized actor types, a value of the corresponding struct type will be returned.
-func (a *Activity) Actor() (actor interface{}) {
-	var obj unknownResource
-	if err := json.Unmarshal(a.RawActor, &obj); err != nil {
-		panic(err.Error())
-	}
-	switch *obj.Type {
-	case UserType:
-		actor = &User{}
-	case ProgramType:
-		actor = &Program{}
-	}
-	if err := json.Unmarshal(a.RawActor, &actor); err != nil {
-		panic(err.Error())
-	}
+// parseActor parses the actor relationship of an activity
+func parseActor(raw json.RawMessage) (interface{}, error) {
+	return parseResource(raw, func(resourceType string) interface{} {
+		switch resourceType {
+		case UserType:
+			return &User{}
+		case ProgramType:
+			return &Program{}
+		}
+		return nil
+	})
+}
+
+// ParseActor returns the parsed actor. For recognized actor types, a value of the corresponding struct type will be returned.
+// Actors of other types are returned as a *Resource. An error is returned if the actor is malformed.
+func (a *Activity) ParseActor() (interface{}, error) {
+	return parseActor(a.RawActor)
+}
+
+// Actor returns the parsed actor, or nil if it is malformed. See ParseActor.
+func (a *Activity) Actor() interface{} {
+	actor, _ := a.ParseActor()
 	return actor
 }
 
-// Activity returns the parsed activity. For recognized activity types, a value of the corresponding struct type will be returned.
-func (a *Activity) Activity() (activity interface{}) {
+// Activity returns the parsed activity, or nil if it is malformed. See ParseActivity.
+func (a *Activity) Activity() ActivityDetail {
+	activity, _ := a.ParseActivity()
+	return activity
+}
+
+// ParseActivity returns the parsed activity. For recognized activity types, a value of the corresponding struct type will be returned.
+// Activities of other types are returned as an *ActivityUnknown. An error is returned if the activity is malformed.
+func (a *Activity) ParseActivity() (ActivityDetail, error) {
+	if a.Type == nil {
+		return nil, nil
+	}
+	var activity ActivityDetail
 	switch *a.Type {
+	case ActivityAgreedOnGoingPublicType:
+		activity = &ActivityAgreedOnGoingPublic{}
 	case ActivityBountyAwardedType:
 		activity = &ActivityBountyAwarded{}
 	case ActivityBountySuggestedType:
 		activity = &ActivityBountySuggested{}
 	case ActivityBugClonedType:
 		activity = &ActivityBugCloned{}
+	case ActivityBugDuplicateType:
+		activity = &ActivityBugDuplicate{}
+	case ActivityBugFiledType:
+		activity = &ActivityBugFiled{}
+	case ActivityBugInactiveType:
+		activity = &ActivityBugInactive{}
+	case ActivityBugInformativeType:
+		activity = &ActivityBugInformative{}
+	case ActivityBugNeedsMoreInfoType:
+		activity = &ActivityBugNeedsMoreInfo{}
+	case ActivityBugNewType:
+		activity = &ActivityBugNew{}
+	case ActivityBugNotApplicableType:
+		activity = &ActivityBugNotApplicable{}
+	case ActivityBugReopenedType:
+		activity = &ActivityBugReopened{}
+	case ActivityBugResolvedType:
+		activity = &ActivityBugResolved{}
+	case ActivityBugRetestingType:
+		activity = &ActivityBugRetesting{}
+	case ActivityBugSpamType:
+		activity = &ActivityBugSpam{}
+	case ActivityBugTriagedType:
+		activity = &ActivityBugTriaged{}
+	case ActivityCancelledDisclosureRequestType:
+		activity = &ActivityCancelledDisclosureRequest{}
+	case ActivityChangedScopeType:
+		activity = &ActivityChangedScope{}
+	case ActivityCommentType:
+		activity = &ActivityComment{}
+	case ActivityCommentsClosedType:
+		activity = &ActivityCommentsClosed{}
+	case ActivityCommentsOpenedType:
+		activity = &ActivityCommentsOpened{}
+	case ActivityCVEIDAddedType:
+		activity = &ActivityCVEIDAdded{}
+	case ActivityExternalAdvisoryAddedType:
+		activity = &ActivityExternalAdvisoryAdded{}
 	case ActivityExternalUserInvitationCancelledType:
 		activity = &ActivityExternalUserInvitationCancelled{}
 	case ActivityExternalUserInvitedType:
@@ -106,25 +169,65 @@ func (a *Activity) Activity() (activity interface{}) {
 		activity = &ActivityExternalUserRemoved{}
 	case ActivityGroupAssignedToBugType:
 		activity = &ActivityGroupAssignedToBug{}
+	case ActivityHackerRequestedMediationType:
+		activity = &ActivityHackerRequestedMediation{}
+	case ActivityManuallyDisclosedType:
+		activity = &ActivityManuallyDisclosed{}
+	case ActivityMediationRequestedType:
+		activity = &ActivityMediationRequested{}
+	case ActivityNobodyAssignedToBugType:
+		activity = &ActivityNobodyAssignedToBug{}
+	case ActivityNotEligibleForBountyType:
+		activity = &ActivityNotEligibleForBounty{}
+	case ActivityProgramInactiveType:
+		activity = &ActivityProgramInactive{}
 	case ActivityReferenceIDAddedType:
 		activity = &ActivityReferenceIDAdded{}
+	case ActivityReportBecamePublicType:
+		activity = &ActivityReportBecamePublic{}
+	case ActivityReportCollaboratorInvitedType:
+		activity = &ActivityReportCollaboratorInvited{}
+	case ActivityReportCollaboratorJoinedType:
+		activity = &ActivityReportCollaboratorJoined{}
+	case ActivityReportRetestApprovedType:
+		activity = &ActivityReportRetestApproved{}
+	case ActivityReportRetestRejectedType:
+		activity = &ActivityReportRetestRejected{}
+	case ActivityReportSeverityUpdatedType:
+		activity = &ActivityReportSeverityUpdated{}
 	case ActivityReportTitleUpdatedType:
 		activity = &ActivityReportTitleUpdated{}
 	case ActivityReportVulnerabilityTypesUpdatedType:
 		activity = &ActivityReportVulnerabilityTypesUpdated{}
+	case ActivityRetestUserExpiredType:
+		activity = &ActivityRetestUserExpired{}
 	case ActivitySwagAwardedType:
 		activity = &ActivitySwagAwarded{}
 	case ActivityUserAssignedToBugType:
 		activity = &ActivityUserAssignedToBug{}
 	case ActivityUserBannedFromProgramType:
 		activity = &ActivityUserBannedFromProgram{}
+	case ActivityUserCompletedRetestType:
+		activity = &ActivityUserCompletedRetest{}
+	case ActivityUserLeftRetestType:
+		activity = &ActivityUserLeftRetest{}
 	default:
-		return nil
+		resource, err := parseResource(a.rawData, func(string) interface{} { return nil })
+		if resource == nil || err != nil {
+			return nil, err
+		}
+		return &ActivityUnknown{Resource: *resource.(*Resource)}, nil
 	}
 	if err := json.Unmarshal(a.rawData, activity); err != nil {
-		panic(err.Error())
+		return nil, err
 	}
-	return activity
+	return activity, nil
+}
+
+// ActivityUnknown is the detail of an activity whose type is not known to this package yet.
+// The raw JSONAPI resource is kept so that its attributes can still be inspected.
+type ActivityUnknown struct {
+	Resource
 }
 
 // Report returns the report this activity is a child of
@@ -132,6 +235,29 @@ func (a *Activity) Report() *Report {
 	return a.report
 }
 
+// ActivityAgreedOnGoingPublic occurs when both parties agreed on disclosing a report.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-agreed-on-going-public
+type ActivityAgreedOnGoingPublic struct {
+	FirstToAgree *bool `json:"first_to_agree"`
+}
+
+// Helper types for JSONUnmarshal
+type activityAgreedOnGoingPublic ActivityAgreedOnGoingPublic // Used to avoid recursion of JSONUnmarshal
+type activityAgreedOnGoingPublicUnmarshalHelper struct {
+	Attributes activityAgreedOnGoingPublic `json:"attributes"`
+}
+
+// UnmarshalJSON allows JSONAPI attributes and relationships to unmarshal cleanly.
+func (a *ActivityAgreedOnGoingPublic) UnmarshalJSON(b []byte) error {
+	var helper activityAgreedOnGoingPublicUnmarshalHelper
+	if err := json.Unmarshal(b, &helper); err != nil {
+		return err
+	}
+	*a = ActivityAgreedOnGoingPublic(helper.Attributes)
+	return nil
+}
+
 // ActivityBountyAwarded occurs when a bounty is awarded.
 //
 // HackerOne API docs:https://api.hackerone.com/reference/#activity-activity-bounty-awarded
@@ -203,6 +329,163 @@ func (a *ActivityBugCloned) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// ActivityBugDuplicate occurs when a report is closed as a duplicate.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-bug-duplicate
+type ActivityBugDuplicate struct {
+	OriginalReportID *int `json:"original_report_id"`
+}
+
+// Helper types for JSONUnmarshal
+type activityBugDuplicate ActivityBugDuplicate // Used to avoid recursion of JSONUnmarshal
+type activityBugDuplicateUnmarshalHelper struct {
+	Attributes activityBugDuplicate `json:"attributes"`
+}
+
+// UnmarshalJSON allows JSONAPI attributes and relationships to unmarshal cleanly.
+func (a *ActivityBugDuplicate) UnmarshalJSON(b []byte) error {
+	var helper activityBugDuplicateUnmarshalHelper
+	if err := json.Unmarshal(b, &helper); err != nil {
+		return err
+	}
+	*a = ActivityBugDuplicate(helper.Attributes)
+	return nil
+}
+
+// ActivityBugFiled occurs when a report is filed by a hacker on behalf of the program.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-bug-filed
+type ActivityBugFiled struct{}
+
+// ActivityBugInactive occurs when a report is closed because it has been inactive.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-bug-inactive
+type ActivityBugInactive struct{}
+
+// ActivityBugInformative occurs when a report is closed as informative.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-bug-informative
+type ActivityBugInformative struct{}
+
+// ActivityBugNeedsMoreInfo occurs when more information is requested from the reporter.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-bug-needs-more-info
+type ActivityBugNeedsMoreInfo struct{}
+
+// ActivityBugNew occurs when a report is moved back to the new state.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-bug-new
+type ActivityBugNew struct{}
+
+// ActivityBugNotApplicable occurs when a report is closed as not applicable.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-bug-not-applicable
+type ActivityBugNotApplicable struct{}
+
+// ActivityBugReopened occurs when a closed report is reopened.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-bug-reopened
+type ActivityBugReopened struct{}
+
+// ActivityBugResolved occurs when a report is closed as resolved.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-bug-resolved
+type ActivityBugResolved struct{}
+
+// ActivityBugRetesting occurs when a report is sent to a hacker for retesting.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-bug-retesting
+type ActivityBugRetesting struct{}
+
+// ActivityBugSpam occurs when a report is closed as spam.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-bug-spam
+type ActivityBugSpam struct{}
+
+// ActivityBugTriaged occurs when a report is triaged.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-bug-triaged
+type ActivityBugTriaged struct{}
+
+// ActivityCancelledDisclosureRequest occurs when a disclosure request is cancelled.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-cancelled-disclosure-request
+type ActivityCancelledDisclosureRequest struct{}
+
+// ActivityChangedScope occurs when the structured scope of a report is changed.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-changed-scope
+type ActivityChangedScope struct {
+	OldScope *StructuredScope `json:"old_scope"`
+	NewScope *StructuredScope `json:"new_scope"`
+}
+
+// Helper types for JSONUnmarshal
+type activityChangedScopeUnmarshalHelper struct {
+	Relationships struct {
+		OldScope struct {
+			Data *StructuredScope `json:"data"`
+		} `json:"old_scope"`
+		NewScope struct {
+			Data *StructuredScope `json:"data"`
+		} `json:"new_scope"`
+	} `json:"relationships"`
+}
+
+// UnmarshalJSON allows JSONAPI attributes and relationships to unmarshal cleanly.
+func (a *ActivityChangedScope) UnmarshalJSON(b []byte) error {
+	var helper activityChangedScopeUnmarshalHelper
+	if err := json.Unmarshal(b, &helper); err != nil {
+		return err
+	}
+	a.OldScope = helper.Relationships.OldScope.Data
+	a.NewScope = helper.Relationships.NewScope.Data
+	return nil
+}
+
+// ActivityComment occurs when a comment is added to a report.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-comment
+type ActivityComment struct{}
+
+// ActivityCommentsClosed occurs when comments are closed on a report.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-comments-closed
+type ActivityCommentsClosed struct{}
+
+// ActivityCommentsOpened occurs when comments are reopened on a report.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-comments-opened
+type ActivityCommentsOpened struct{}
+
+// ActivityCVEIDAdded occurs when CVE IDs are added to a report.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-cve-id-added
+type ActivityCVEIDAdded struct {
+	CVEIDs []string `json:"cve_ids"`
+}
+
+// Helper types for JSONUnmarshal
+type activityCVEIDAdded ActivityCVEIDAdded // Used to avoid recursion of JSONUnmarshal
+type activityCVEIDAddedUnmarshalHelper struct {
+	Attributes activityCVEIDAdded `json:"attributes"`
+}
+
+// UnmarshalJSON allows JSONAPI attributes and relationships to unmarshal cleanly.
+func (a *ActivityCVEIDAdded) UnmarshalJSON(b []byte) error {
+	var helper activityCVEIDAddedUnmarshalHelper
+	if err := json.Unmarshal(b, &helper); err != nil {
+		return err
+	}
+	*a = ActivityCVEIDAdded(helper.Attributes)
+	return nil
+}
+
+// ActivityExternalAdvisoryAdded occurs when an external advisory is added to a report.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-external-advisory-added
+type ActivityExternalAdvisoryAdded struct{}
+
 // ActivityExternalUserInvitationCancelled occurs when a external user's invitiation is cancelled.
 //
 // HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-external-user-invitation-cancelled
@@ -326,6 +609,36 @@ func (a *ActivityGroupAssignedToBug) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// ActivityHackerRequestedMediation occurs when the reporter requests mediation.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-hacker-requested-mediation
+type ActivityHackerRequestedMediation struct{}
+
+// ActivityManuallyDisclosed occurs when a report is manually disclosed.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-manually-disclosed
+type ActivityManuallyDisclosed struct{}
+
+// ActivityMediationRequested occurs when mediation is requested.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-mediation-requested
+type ActivityMediationRequested struct{}
+
+// ActivityNobodyAssignedToBug occurs when the assignee of a report is removed.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-nobody-assigned-to-bug
+type ActivityNobodyAssignedToBug struct{}
+
+// ActivityNotEligibleForBounty occurs when a report is marked as not eligible for a bounty.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-not-eligible-for-bounty
+type ActivityNotEligibleForBounty struct{}
+
+// ActivityProgramInactive occurs when a report is closed because the program is inactive.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-program-inactive
+type ActivityProgramInactive struct{}
+
 // ActivityReferenceIDAdded occurs when a reference id/url is added to a report.
 //
 // HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-reference-id-added
@@ -350,6 +663,36 @@ func (a *ActivityReferenceIDAdded) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// ActivityReportBecamePublic occurs when a report is disclosed publicly.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-report-became-public
+type ActivityReportBecamePublic struct{}
+
+// ActivityReportCollaboratorInvited occurs when a collaborator is invited to a report.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-report-collaborator-invited
+type ActivityReportCollaboratorInvited struct{}
+
+// ActivityReportCollaboratorJoined occurs when an invited collaborator joins a report.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-report-collaborator-joined
+type ActivityReportCollaboratorJoined struct{}
+
+// ActivityReportRetestApproved occurs when the retest of a report is approved.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-report-retest-approved
+type ActivityReportRetestApproved struct{}
+
+// ActivityReportRetestRejected occurs when the retest of a report is rejected.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-report-retest-rejected
+type ActivityReportRetestRejected struct{}
+
+// ActivityReportSeverityUpdated occurs when the severity of a report is updated.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-report-severity-updated
+type ActivityReportSeverityUpdated struct{}
+
 // ActivityReportTitleUpdated occurs when report title is updated
 //
 // HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-report-title-updated
@@ -406,6 +749,11 @@ func (a *ActivityReportVulnerabilityTypesUpdated) UnmarshalJSON(b []byte) error
 	return nil
 }
 
+// ActivityRetestUserExpired occurs when a hacker did not complete a retest in time.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-retest-user-expired
+type ActivityRetestUserExpired struct{}
+
 // ActivitySwagAwarded occurs when swag is awarded
 //
 // HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-swag-awarded
@@ -486,3 +834,13 @@ func (a *ActivityUserBannedFromProgram) UnmarshalJSON(b []byte) error {
 	a.RemovedUser = helper.Relationships.RemovedUser.Data
 	return nil
 }
+
+// ActivityUserCompletedRetest occurs when a hacker completes a retest.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-user-completed-retest
+type ActivityUserCompletedRetest struct{}
+
+// ActivityUserLeftRetest occurs when a hacker leaves a retest.
+//
+// HackerOne API docs: https://api.hackerone.com/reference/#activity-activity-user-left-retest
+type ActivityUserLeftRetest struct{}
@@ -0,0 +1,272 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+)
+
+// ActivityService handles communication with the activity related methods of the H1 API.
+type ActivityService service
+
+// activityCursorVersion is bumped whenever the encoding of activity cursors changes. Version 1 cursors were exclusive
+// and are still accepted.
+const activityCursorVersion = 2
+
+// activityCursorOverlap is how far before the cursor activities are requested again, so the cursor is included even if
+// the API truncates updated_at_after to whole seconds. Activities which were already returned are skipped.
+const activityCursorOverlap = time.Second
+
+// activityCursor is the decoded form of the opaque cursor returned by ActivityService.ListIncremental
+type activityCursor struct {
+	Version        int       `json:"v"`
+	UpdatedAtAfter time.Time `json:"updated_at_after"`
+	// IDs of the activities updated exactly at UpdatedAtAfter which were already returned
+	BoundaryIDs []string `json:"boundary_ids,omitempty"`
+}
+
+// NewActivityCursor returns a cursor for ActivityService.ListIncremental which starts with the activities updated at the
+// given time. An empty cursor starts from the very first activity of the program.
+func NewActivityCursor(since time.Time) string {
+	return encodeActivityCursor(activityCursor{Version: activityCursorVersion, UpdatedAtAfter: since.UTC()})
+}
+
+func encodeActivityCursor(cursor activityCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeActivityCursor(cursor string) (activityCursor, error) {
+	var decoded activityCursor
+	if cursor == "" {
+		return decoded, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(data, &decoded)
+	}
+	if err != nil || decoded.Version < 1 || decoded.Version > activityCursorVersion {
+		return decoded, &ValidationError{Parameter: "cursor", Detail: "invalid activity cursor"}
+	}
+	return decoded, nil
+}
+
+// updatedAtAfter returns the updated_at_after query parameter of the cursor
+func (c *activityCursor) updatedAtAfter() string {
+	switch {
+	case c.UpdatedAtAfter.IsZero():
+		return ""
+	case c.Version == 1:
+		return c.UpdatedAtAfter.Format(time.RFC3339Nano)
+	}
+	return c.UpdatedAtAfter.Add(-activityCursorOverlap).Format(time.RFC3339Nano)
+}
+
+// returned reports whether the activity was already returned before the cursor
+func (c *activityCursor) returned(activity *Activity) bool {
+	updatedAt := activityUpdatedAt(activity)
+	if updatedAt.Before(c.UpdatedAtAfter) {
+		return true
+	}
+	if !updatedAt.Equal(c.UpdatedAtAfter) || c.UpdatedAtAfter.IsZero() {
+		return false
+	}
+	if c.Version == 1 {
+		return true
+	}
+	for _, id := range c.BoundaryIDs {
+		if id == stringValue(activity.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// advance moves the cursor past the given activity
+func (c *activityCursor) advance(activity *Activity) {
+	updatedAt := activityUpdatedAt(activity)
+	switch {
+	case updatedAt.After(c.UpdatedAtAfter):
+		c.UpdatedAtAfter = updatedAt
+		c.BoundaryIDs = []string{stringValue(activity.ID)}
+	case updatedAt.Equal(c.UpdatedAtAfter):
+		c.BoundaryIDs = append(c.BoundaryIDs[:len(c.BoundaryIDs):len(c.BoundaryIDs)], stringValue(activity.ID))
+	default:
+		return
+	}
+	c.Version = activityCursorVersion
+}
+
+// incrementalActivitiesOptions specifies the query of the incremental activities endpoint
+type incrementalActivitiesOptions struct {
+	Handle         string `url:"handle"`
+	UpdatedAtAfter string `url:"updated_at_after,omitempty"`
+}
+
+// ListIncremental fetches the activities of all reports of the program with the given handle which were updated after
+// the cursor, oldest first. It returns the cursor to pass to the next call, which is unchanged if there were no new
+// activities. Activity.Report of the returned activities is a stub which only has its ID set.
+//
+// The cursor is inclusive: it resumes at the time of the last returned activity and only skips the activities which
+// were already returned at that time, so an activity which shows up later with the same timestamp is not missed. The
+// next pages linked from the response are fetched by ListAllIncremental.
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#incremental-activities-get-all-activities
+func (s *ActivityService) ListIncremental(handle, cursor string, listOpts *ListOptions) ([]Activity, string, *Response, error) {
+	return s.ListIncrementalWithContext(context.Background(), handle, cursor, listOpts)
+}
+
+// ListIncrementalWithContext fetches the activities of all reports of the program with the given handle which were
+// updated after the cursor, oldest first, using the provided context. It returns the cursor to pass to the next call.
+// See ListIncremental.
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#incremental-activities-get-all-activities
+func (s *ActivityService) ListIncrementalWithContext(ctx context.Context, handle, cursor string, listOpts *ListOptions) ([]Activity, string, *Response, error) {
+	decoded, err := decodeActivityCursor(cursor)
+	if err != nil {
+		return nil, cursor, nil, err
+	}
+	opts := incrementalActivitiesOptions{Handle: handle, UpdatedAtAfter: decoded.updatedAtAfter()}
+	// addOptions takes structs only so it can't fail
+	u, _ := addOptions("incremental/activities", &opts, listOpts)
+
+	activities, resp, err := s.fetchIncremental(ctx, u, &decoded)
+	if err != nil {
+		return nil, cursor, resp, err
+	}
+
+	next := decoded
+	for idx := range activities {
+		next.advance(&activities[idx])
+	}
+	if len(activities) > 0 {
+		cursor = encodeActivityCursor(next)
+	}
+
+	return activities, cursor, resp, err
+}
+
+// ListAllIncremental fetches all activities of the program with the given handle which were updated after the cursor,
+// following the pages of the response up to the last one. See ListIncremental.
+func (s *ActivityService) ListAllIncremental(handle, cursor string) ([]Activity, string, *Response, error) {
+	return s.ListAllIncrementalWithContext(context.Background(), handle, cursor)
+}
+
+// ListAllIncrementalWithContext fetches all activities of the program with the given handle which were updated after
+// the cursor using the provided context, following the next page links of the responses up to the last page. On error,
+// the activities fetched so far are returned with the given cursor, so resuming from it may return some of them again.
+func (s *ActivityService) ListAllIncrementalWithContext(ctx context.Context, handle, cursor string) ([]Activity, string, *Response, error) {
+	decoded, err := decodeActivityCursor(cursor)
+	if err != nil {
+		return nil, cursor, nil, err
+	}
+	opts := incrementalActivitiesOptions{Handle: handle, UpdatedAtAfter: decoded.updatedAtAfter()}
+	// addOptions takes structs only so it can't fail
+	u, _ := addOptions("incremental/activities", &opts, &ListOptions{PageSize: defaultPageSize})
+
+	var all []Activity
+	var resp *Response
+	next := decoded
+	for {
+		activities, pageResp, err := s.fetchIncremental(ctx, u, &decoded)
+		if pageResp != nil {
+			resp = pageResp
+		}
+		if err != nil {
+			return all, cursor, resp, err
+		}
+		all = append(all, activities...)
+		for idx := range activities {
+			next.advance(&activities[idx])
+		}
+		if resp.Links.Next == "" {
+			break
+		}
+		if resp.Links.Next == u {
+			return all, cursor, resp, errPaginationLoop
+		}
+		u = resp.Links.Next
+	}
+	if len(all) > 0 {
+		cursor = encodeActivityCursor(next)
+	}
+	return all, cursor, resp, nil
+}
+
+// fetchIncremental fetches the page of incremental activities at the given URL, leaving out the ones which were
+// already returned before the cursor
+func (s *ActivityService) fetchIncremental(ctx context.Context, u string, cursor *activityCursor) ([]Activity, *Response, error) {
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data := new([]json.RawMessage)
+	resp, err := s.client.Do(req, data)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	activities := make([]Activity, 0, len(*data))
+	for _, raw := range *data {
+		var activity Activity
+		if err := parseIncrementalActivity(raw, &activity); err != nil {
+			return nil, resp, err
+		}
+		if !cursor.returned(&activity) {
+			activities = append(activities, activity)
+		}
+	}
+	return activities, resp, nil
+}
+
+// parseIncrementalActivity decodes an activity and links it to a stub of the report it belongs to
+func parseIncrementalActivity(raw json.RawMessage, activity *Activity) error {
+	if err := json.Unmarshal(raw, activity); err != nil {
+		return err
+	}
+	var helper struct {
+		Attributes struct {
+			ReportID json.Number `json:"report_id"`
+		} `json:"attributes"`
+	}
+	if err := json.Unmarshal(raw, &helper); err != nil {
+		return err
+	}
+	if reportID := helper.Attributes.ReportID.String(); reportID != "" {
+		activity.report = &Report{ID: String(reportID), Type: String(ReportType)}
+	}
+	return nil
+}
+
+// activityUpdatedAt returns when the activity was last updated, falling back to its creation
+func activityUpdatedAt(activity *Activity) time.Time {
+	if activity.UpdatedAt != nil {
+		return activity.UpdatedAt.Time
+	}
+	if activity.CreatedAt != nil {
+		return activity.CreatedAt.Time
+	}
+	return time.Time{}
+}
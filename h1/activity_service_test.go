@@ -0,0 +1,222 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// incrementalServer serves activities of reports 1 and 2 which were updated in groups of tied activities a minute apart
+func incrementalServer(t *testing.T, count, tied int, queries *[]url.Values) (*httptest.Server, *Client) {
+	start := time.Date(2016, 2, 2, 4, 5, 6, 0, time.UTC)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/incremental/activities", r.URL.Path)
+		query := r.URL.Query()
+		*queries = append(*queries, query)
+		after := start.Add(-time.Second)
+		if value := query.Get("updated_at_after"); value != "" {
+			var err error
+			after, err = time.Parse(time.RFC3339Nano, value)
+			assert.Nil(t, err)
+		}
+		var data []string
+		for idx := 0; idx < count; idx++ {
+			updatedAt := start.Add(time.Duration(idx/tied) * time.Minute)
+			if !updatedAt.After(after) {
+				continue
+			}
+			data = append(data, fmt.Sprintf(`{"id":"%d","type":"activity-comment","attributes":{"report_id":%d,"updated_at":"%s"}}`,
+				idx, idx%2+1, updatedAt.Format(time.RFC3339Nano)))
+		}
+		size, _ := strconv.Atoi(query.Get("page[size]"))
+		page, _ := strconv.Atoi(query.Get("page[number]"))
+		if page == 0 {
+			page = 1
+		}
+		var next string
+		if size > 0 {
+			if len(data) > page*size {
+				nextQuery := r.URL.Query()
+				nextQuery.Set("page[number]", strconv.Itoa(page+1))
+				next = server.URL + r.URL.Path + "?" + nextQuery.Encode()
+				data = data[(page-1)*size : page*size]
+			} else if len(data) > (page-1)*size {
+				data = data[(page-1)*size:]
+			} else {
+				data = nil
+			}
+		}
+		fmt.Fprintf(w, `{"data":[%s],"links":{"next":"%s"}}`, strings.Join(data, ","), next)
+	}))
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+	return server, c
+}
+
+func Test_ActivityService_ListIncremental(t *testing.T) {
+	var queries []url.Values
+	server, c := incrementalServer(t, 3, 1, &queries)
+	defer server.Close()
+
+	// Verify that the first page starts from the beginning and links activities to their report
+	activities, cursor, _, err := c.Activity.ListIncremental("security", "", &ListOptions{PageSize: 2})
+	require.Nil(t, err)
+	require.Len(t, activities, 2)
+	assert.Equal(t, "security", queries[0].Get("handle"))
+	assert.Equal(t, "", queries[0].Get("updated_at_after"))
+	assert.Equal(t, String("1"), activities[0].Report().ID)
+	assert.Equal(t, String("2"), activities[1].Report().ID)
+	assert.NotEmpty(t, cursor)
+
+	// Verify that the cursor resumes after the last activity
+	activities, cursor, _, err = c.Activity.ListIncremental("security", cursor, &ListOptions{PageSize: 2})
+	require.Nil(t, err)
+	require.Len(t, activities, 1)
+	assert.Equal(t, String("2"), activities[0].ID)
+	assert.Equal(t, "2016-02-02T04:06:05Z", queries[1].Get("updated_at_after"))
+
+	// Verify that the cursor is unchanged when there are no new activities
+	activities, next, _, err := c.Activity.ListIncremental("security", cursor, nil)
+	require.Nil(t, err)
+	assert.Empty(t, activities)
+	assert.Equal(t, cursor, next)
+
+	// Verify that a version 1 cursor is still accepted and stays exclusive
+	activities, _, _, err = c.Activity.ListIncremental("security", encodeActivityCursor(activityCursor{
+		Version:        1,
+		UpdatedAtAfter: time.Date(2016, 2, 2, 4, 6, 6, 0, time.UTC),
+	}), nil)
+	require.Nil(t, err)
+	require.Len(t, activities, 1)
+	assert.Equal(t, String("2"), activities[0].ID)
+	assert.Equal(t, "2016-02-02T04:06:06Z", queries[len(queries)-1].Get("updated_at_after"))
+
+	// Verify that a cursor can be created from a time
+	activities, _, _, err = c.Activity.ListIncremental("security", NewActivityCursor(time.Date(2016, 2, 2, 4, 5, 30, 0, time.UTC)), nil)
+	require.Nil(t, err)
+	assert.Len(t, activities, 2)
+
+	// Verify that an invalid cursor is rejected before sending a request
+	count := len(queries)
+	_, next, _, err = c.Activity.ListIncremental("security", "not a cursor", nil)
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.Equal(t, "not a cursor", next)
+	assert.Len(t, queries, count)
+}
+
+func Test_ActivityService_ListAllIncremental(t *testing.T) {
+	var queries []url.Values
+	server, c := incrementalServer(t, 250, 1, &queries)
+	defer server.Close()
+
+	// Verify that all activities are fetched by following the pages of a single cursor
+	activities, cursor, _, err := c.Activity.ListAllIncremental("security", "")
+	require.Nil(t, err)
+	assert.Len(t, activities, 250)
+	assert.Equal(t, String("249"), activities[249].ID)
+	require.Len(t, queries, 3)
+	for idx, page := range []string{"", "2", "3"} {
+		assert.Equal(t, "", queries[idx].Get("updated_at_after"))
+		assert.Equal(t, page, queries[idx].Get("page[number]"))
+	}
+
+	// Verify that resuming from the returned cursor only fetches new activities
+	activities, next, _, err := c.Activity.ListAllIncremental("security", cursor)
+	require.Nil(t, err)
+	assert.Empty(t, activities)
+	assert.Equal(t, cursor, next)
+	assert.Equal(t, "2016-02-02T08:14:05Z", queries[3].Get("updated_at_after"))
+
+	// Verify that an invalid cursor is rejected before sending a request
+	_, next, _, err = c.Activity.ListAllIncremental("security", "not a cursor")
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.Equal(t, "not a cursor", next)
+	assert.Len(t, queries, 4)
+}
+
+func Test_ActivityService_ListAllIncremental_TiedPageBoundary(t *testing.T) {
+	var queries []url.Values
+	// Activities 99 to 101 share a timestamp across the boundary of the first two pages
+	server, c := incrementalServer(t, 150, 3, &queries)
+	defer server.Close()
+
+	// Verify that no tied activity is skipped
+	activities, cursor, _, err := c.Activity.ListAllIncremental("security", "")
+	require.Nil(t, err)
+	require.Len(t, activities, 150)
+	for idx, activity := range activities {
+		assert.Equal(t, String(strconv.Itoa(idx)), activity.ID)
+	}
+	assert.Len(t, queries, 2)
+
+	// Verify that the cursor resumes at the last activity and skips the ones tied with it
+	activities, _, _, err = c.Activity.ListAllIncremental("security", cursor)
+	require.Nil(t, err)
+	assert.Empty(t, activities)
+	assert.Equal(t, "2016-02-02T04:54:05Z", queries[2].Get("updated_at_after"))
+}
+
+func Test_ActivityService_ListIncremental_LateBoundaryActivity(t *testing.T) {
+	updatedAt := "2016-02-02T04:05:06Z"
+	data := []string{
+		fmt.Sprintf(`{"id":"1","type":"activity-comment","attributes":{"report_id":1,"updated_at":"%s"}}`, updatedAt),
+	}
+	var next string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"data":[%s],"links":{"next":"%s"}}`, strings.Join(data, ","), next)
+	}))
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	activities, cursor, _, err := c.Activity.ListAllIncremental("security", "")
+	require.Nil(t, err)
+	require.Len(t, activities, 1)
+
+	// Verify that an activity which shows up later with the timestamp of the cursor is not skipped
+	data = append(data, fmt.Sprintf(`{"id":"2","type":"activity-comment","attributes":{"report_id":1,"updated_at":"%s"}}`, updatedAt))
+	activities, cursor, _, err = c.Activity.ListAllIncremental("security", cursor)
+	require.Nil(t, err)
+	require.Len(t, activities, 1)
+	assert.Equal(t, String("2"), activities[0].ID)
+	activities, unchanged, _, err := c.Activity.ListIncremental("security", cursor, nil)
+	require.Nil(t, err)
+	assert.Empty(t, activities)
+	assert.Equal(t, cursor, unchanged)
+
+	// Verify that a next page link to the current page is not followed forever
+	next = server.URL + "/incremental/activities?handle=security&page%5Bsize%5D=100"
+	_, resumed, _, err := c.Activity.ListAllIncremental("security", "")
+	assert.Equal(t, errPaginationLoop, err)
+	assert.Equal(t, "", resumed)
+}
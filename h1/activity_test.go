@@ -24,33 +24,59 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"encoding/json"
 	"io/ioutil"
 	"testing"
 )
 
-func Test_ActivityActor_Panic(t *testing.T) {
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Activity.Actor() with invalid JSON should panic")
-			}
-		}()
-		actual := Activity{
-			RawActor: []byte("Invalid JSON"),
-		}
-		actual.Actor()
-	}()
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Activity.Actor() with incorrect JSON should panic")
-			}
-		}()
-		actual := Activity{
-			RawActor: []byte(`{"type":"user","id":123}`),
-		}
-		actual.Actor()
-	}()
+func Test_ActivityActor_Error(t *testing.T) {
+	actual := Activity{
+		RawActor: []byte("Invalid JSON"),
+	}
+	_, err := actual.ParseActor()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Actor())
+
+	actual = Activity{
+		RawActor: []byte(`{"type":"user","id":123}`),
+	}
+	_, err = actual.ParseActor()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Actor())
+
+	// A missing type used to be dereferenced
+	actual = Activity{
+		RawActor: []byte(`{"id":"123"}`),
+	}
+	actor, err := actual.ParseActor()
+	assert.Nil(t, err)
+	assert.Nil(t, actor)
+
+	// A malformed actor relationship fails unmarshalling the activity
+	err = json.Unmarshal([]byte(`{"id":"1337","type":"activity-comment","relationships":{"actor":{"data":{"type":"user","id":123}}}}`), &actual)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "malformed actor of activity 1337")
+	err = json.Unmarshal([]byte(`{"id":"1337","type":"activity-comment","relationships":{"actor":{"data":"user"}}}`), &actual)
+	assert.NotNil(t, err)
+
+	// Changing the raw actor after unmarshalling is reflected
+	actual.RawActor = []byte(`{"type":"user","id":"123"}`)
+	actor, err = actual.ParseActor()
+	assert.Nil(t, err)
+	assert.Equal(t, &User{ID: String("123"), Type: String(UserType)}, actor)
+}
+
+func Test_ActivityActor_Unknown(t *testing.T) {
+	var actual Activity
+	err := json.Unmarshal([]byte(`{"id":"1337","type":"activity-comment","relationships":{"actor":{"data":{"id":"42","type":"bot","attributes":{"name":"Hai"}}}}}`), &actual)
+	require.Nil(t, err)
+	actor, err := actual.ParseActor()
+	assert.Nil(t, err)
+	assert.Equal(t, &Resource{
+		ID:         String("42"),
+		Type:       String("bot"),
+		Attributes: json.RawMessage(`{"name":"Hai"}`),
+	}, actor)
 }
 
 func Test_ActivityActor_User(t *testing.T) {
@@ -147,24 +173,34 @@ func Test_ActivityActor_Program(t *testing.T) {
 }
 
 func Test_ActivityActivity_Nil(t *testing.T) {
+	actual := Activity{}
+	actualActivity, err := actual.ParseActivity()
+	assert.Nil(t, err)
+	assert.Nil(t, actualActivity)
+}
+
+func Test_ActivityActivity_Unknown(t *testing.T) {
 	actual := Activity{
-		Type: String("unknown"),
+		Type:    String("unknown"),
+		rawData: []byte(`{"id":"1337","type":"unknown","attributes":{"message":"?"}}`),
 	}
-	actualActor := actual.Activity()
-	assert.Nil(t, actualActor)
+	actualActivity, err := actual.ParseActivity()
+	assert.Nil(t, err)
+	assert.Equal(t, &ActivityUnknown{Resource{
+		ID:         String("1337"),
+		Type:       String("unknown"),
+		Attributes: json.RawMessage(`{"message":"?"}`),
+	}}, actualActivity)
 }
 
-func Test_ActivityActivity_Panic(t *testing.T) {
-	defer func() {
-		if recover() == nil {
-			assert.Fail(t, "Activity.Activity() with invalid JSON should panic")
-		}
-	}()
+func Test_ActivityActivity_Error(t *testing.T) {
 	actual := Activity{
 		Type:    String(ActivityBountyAwardedType),
 		rawData: []byte("Invalid JSON"),
 	}
-	actual.Activity()
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
 }
 
 func Test_ActivityAgreedOnGoingPublic(t *testing.T) {
@@ -178,6 +214,17 @@ func Test_ActivityAgreedOnGoingPublic(t *testing.T) {
 		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 	}
+	actualActivity := actual.Activity().(*ActivityAgreedOnGoingPublic)
+	expectedActivity := &ActivityAgreedOnGoingPublic{
+		FirstToAgree: Bool(true),
+	}
+	assert.Equal(t, expectedActivity, actualActivity)
+
+	actual.rawData = []byte(`{"attributes":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
+
 	actual.rawData = nil
 	actual.RawActor = nil
 	assert.Equal(t, expected, actual)
@@ -202,15 +249,10 @@ func Test_ActivityBountyAwarded(t *testing.T) {
 	}
 	assert.Equal(t, expectedActivity, actualActivity)
 
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Activity.Activity() with incorrect JSON should panic")
-			}
-		}()
-		actual.rawData = []byte(`{"attributes":123}`)
-		actual.Activity()
-	}()
+	actual.rawData = []byte(`{"attributes":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
 
 	actual.rawData = nil
 	actual.RawActor = nil
@@ -237,15 +279,10 @@ func Test_ActivityBountySuggested(t *testing.T) {
 	}
 	assert.Equal(t, expectedActivity, actualActivity)
 
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Activity.Activity() with incorrect JSON should panic")
-			}
-		}()
-		actual.rawData = []byte(`{"attributes":123}`)
-		actual.Activity()
-	}()
+	actual.rawData = []byte(`{"attributes":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
 
 	actual.rawData = nil
 	actual.RawActor = nil
@@ -270,15 +307,10 @@ func Test_ActivityBugCloned(t *testing.T) {
 	}
 	assert.Equal(t, expectedActivity, actualActivity)
 
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Activity.Activity() with incorrect JSON should panic")
-			}
-		}()
-		actual.rawData = []byte(`{"attributes":123}`)
-		actual.Activity()
-	}()
+	actual.rawData = []byte(`{"attributes":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
 
 	actual.rawData = nil
 	actual.RawActor = nil
@@ -296,6 +328,55 @@ func Test_ActivityBugDuplicate(t *testing.T) {
 		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 	}
+	actualActivity := actual.Activity().(*ActivityBugDuplicate)
+	expectedActivity := &ActivityBugDuplicate{
+		OriginalReportID: Int(1336),
+	}
+	assert.Equal(t, expectedActivity, actualActivity)
+
+	actual.rawData = []byte(`{"attributes":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ActivityBugFiled(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-bug-filed.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityBugFiledType),
+		Message:   String("Bug Filed!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	assert.IsType(t, &ActivityBugFiled{}, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ActivityBugInactive(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-bug-inactive.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityBugInactiveType),
+		Message:   String("Bug Inactive!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	assert.IsType(t, &ActivityBugInactive{}, actual.Activity())
+
 	actual.rawData = nil
 	actual.RawActor = nil
 	assert.Equal(t, expected, actual)
@@ -312,6 +393,8 @@ func Test_ActivityBugInformative(t *testing.T) {
 		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 	}
+	assert.IsType(t, &ActivityBugInformative{}, actual.Activity())
+
 	actual.rawData = nil
 	actual.RawActor = nil
 	assert.Equal(t, expected, actual)
@@ -328,6 +411,8 @@ func Test_ActivityBugNeedsMoreInfo(t *testing.T) {
 		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 	}
+	assert.IsType(t, &ActivityBugNeedsMoreInfo{}, actual.Activity())
+
 	actual.rawData = nil
 	actual.RawActor = nil
 	assert.Equal(t, expected, actual)
@@ -344,6 +429,8 @@ func Test_ActivityBugNew(t *testing.T) {
 		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 	}
+	assert.IsType(t, &ActivityBugNew{}, actual.Activity())
+
 	actual.rawData = nil
 	actual.RawActor = nil
 	assert.Equal(t, expected, actual)
@@ -360,6 +447,8 @@ func Test_ActivityBugNotApplicable(t *testing.T) {
 		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 	}
+	assert.IsType(t, &ActivityBugNotApplicable{}, actual.Activity())
+
 	actual.rawData = nil
 	actual.RawActor = nil
 	assert.Equal(t, expected, actual)
@@ -376,6 +465,8 @@ func Test_ActivityBugReopened(t *testing.T) {
 		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 	}
+	assert.IsType(t, &ActivityBugReopened{}, actual.Activity())
+
 	actual.rawData = nil
 	actual.RawActor = nil
 	assert.Equal(t, expected, actual)
@@ -392,6 +483,27 @@ func Test_ActivityBugResolved(t *testing.T) {
 		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 	}
+	assert.IsType(t, &ActivityBugResolved{}, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ActivityBugRetesting(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-bug-retesting.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityBugRetestingType),
+		Message:   String("Bug Retesting!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	assert.IsType(t, &ActivityBugRetesting{}, actual.Activity())
+
 	actual.rawData = nil
 	actual.RawActor = nil
 	assert.Equal(t, expected, actual)
@@ -408,6 +520,8 @@ func Test_ActivityBugSpam(t *testing.T) {
 		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 	}
+	assert.IsType(t, &ActivityBugSpam{}, actual.Activity())
+
 	actual.rawData = nil
 	actual.RawActor = nil
 	assert.Equal(t, expected, actual)
@@ -424,6 +538,76 @@ func Test_ActivityBugTriaged(t *testing.T) {
 		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 	}
+	assert.IsType(t, &ActivityBugTriaged{}, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ActivityCancelledDisclosureRequest(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-cancelled-disclosure-request.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityCancelledDisclosureRequestType),
+		Message:   String("Cancelled Disclosure Request!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	assert.IsType(t, &ActivityCancelledDisclosureRequest{}, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ActivityChangedScope(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-changed-scope.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityChangedScopeType),
+		Message:   String("Changed Scope!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	actualActivity := actual.Activity().(*ActivityChangedScope)
+	expectedActivity := &ActivityChangedScope{
+		OldScope: &StructuredScope{
+			ID:                    String("1337"),
+			Type:                  String(StructuredScopeType),
+			AssetIdentifier:       "www.example.com",
+			AssetType:             "URL",
+			EligibleForBounty:     true,
+			EligibleForSubmission: true,
+			MaxSeverity:           "critical",
+			CreatedAt:             NewTimestamp("2016-02-02T04:05:06.000Z"),
+			UpdatedAt:             NewTimestamp("2016-02-02T04:05:06.000Z"),
+		},
+		NewScope: &StructuredScope{
+			ID:                    String("1338"),
+			Type:                  String(StructuredScopeType),
+			AssetIdentifier:       "api.example.com",
+			AssetType:             "URL",
+			EligibleForBounty:     true,
+			EligibleForSubmission: true,
+			MaxSeverity:           "critical",
+			CreatedAt:             NewTimestamp("2016-02-02T04:05:06.000Z"),
+			UpdatedAt:             NewTimestamp("2016-02-02T04:05:06.000Z"),
+		},
+	}
+	assert.Equal(t, expectedActivity, actualActivity)
+
+	actual.rawData = []byte(`{"relationships":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
+
 	actual.rawData = nil
 	actual.RawActor = nil
 	assert.Equal(t, expected, actual)
@@ -451,6 +635,93 @@ func Test_ActivityComment(t *testing.T) {
 			},
 		},
 	}
+	assert.IsType(t, &ActivityComment{}, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ActivityCommentsClosed(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-comments-closed.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityCommentsClosedType),
+		Message:   String("Comments Closed!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	assert.IsType(t, &ActivityCommentsClosed{}, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ActivityCommentsOpened(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-comments-opened.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityCommentsOpenedType),
+		Message:   String("Comments Opened!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	assert.IsType(t, &ActivityCommentsOpened{}, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ActivityCVEIDAdded(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-cve-id-added.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityCVEIDAddedType),
+		Message:   String("CVE ID Added!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	actualActivity := actual.Activity().(*ActivityCVEIDAdded)
+	expectedActivity := &ActivityCVEIDAdded{
+		CVEIDs: []string{"CVE-2016-1337", "CVE-2016-1338"},
+	}
+	assert.Equal(t, expectedActivity, actualActivity)
+
+	actual.rawData = []byte(`{"attributes":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ActivityExternalAdvisoryAdded(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-external-advisory-added.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityExternalAdvisoryAddedType),
+		Message:   String("External Advisory Added!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	assert.IsType(t, &ActivityExternalAdvisoryAdded{}, actual.Activity())
+
 	actual.rawData = nil
 	actual.RawActor = nil
 	assert.Equal(t, expected, actual)
@@ -474,15 +745,10 @@ func Test_ActivityExternalUserInvitationCancelled(t *testing.T) {
 	}
 	assert.Equal(t, expectedActivity, actualActivity)
 
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Activity.Activity() with incorrect JSON should panic")
-			}
-		}()
-		actual.rawData = []byte(`{"attributes":123}`)
-		actual.Activity()
-	}()
+	actual.rawData = []byte(`{"attributes":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
 
 	actual.rawData = nil
 	actual.RawActor = nil
@@ -507,15 +773,10 @@ func Test_ActivityExternalUserInvited(t *testing.T) {
 	}
 	assert.Equal(t, expectedActivity, actualActivity)
 
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Activity.Activity() with incorrect JSON should panic")
-			}
-		}()
-		actual.rawData = []byte(`{"attributes":123}`)
-		actual.Activity()
-	}()
+	actual.rawData = []byte(`{"attributes":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
 
 	actual.rawData = nil
 	actual.RawActor = nil
@@ -540,15 +801,10 @@ func Test_ActivityExternalUserJoined(t *testing.T) {
 	}
 	assert.Equal(t, expectedActivity, actualActivity)
 
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Activity.Activity() with incorrect JSON should panic")
-			}
-		}()
-		actual.rawData = []byte(`{"attributes":123}`)
-		actual.Activity()
-	}()
+	actual.rawData = []byte(`{"attributes":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
 
 	actual.rawData = nil
 	actual.RawActor = nil
@@ -586,15 +842,10 @@ func Test_ActivityExternalUserRemoved(t *testing.T) {
 	}
 	assert.Equal(t, expectedActivity, actualActivity)
 
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Activity.Activity() with incorrect JSON should panic")
-			}
-		}()
-		actual.rawData = []byte(`{"relationships":123}`)
-		actual.Activity()
-	}()
+	actual.rawData = []byte(`{"relationships":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
 
 	actual.rawData = nil
 	actual.RawActor = nil
@@ -624,15 +875,10 @@ func Test_ActivityGroupAssignedToBug(t *testing.T) {
 	}
 	assert.Equal(t, expectedActivity, actualActivity)
 
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Activity.Activity() with incorrect JSON should panic")
-			}
-		}()
-		actual.rawData = []byte(`{"relationships":123}`)
-		actual.Activity()
-	}()
+	actual.rawData = []byte(`{"relationships":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
 
 	actual.rawData = nil
 	actual.RawActor = nil
@@ -650,6 +896,8 @@ func Test_ActivityHackerRequestedMediation(t *testing.T) {
 		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 	}
+	assert.IsType(t, &ActivityHackerRequestedMediation{}, actual.Activity())
+
 	actual.rawData = nil
 	actual.RawActor = nil
 	assert.Equal(t, expected, actual)
@@ -666,6 +914,8 @@ func Test_ActivityManuallyDisclosed(t *testing.T) {
 		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 	}
+	assert.IsType(t, &ActivityManuallyDisclosed{}, actual.Activity())
+
 	actual.rawData = nil
 	actual.RawActor = nil
 	assert.Equal(t, expected, actual)
@@ -682,6 +932,27 @@ func Test_ActivityMediationRequested(t *testing.T) {
 		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 	}
+	assert.IsType(t, &ActivityMediationRequested{}, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ActivityNobodyAssignedToBug(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-nobody-assigned-to-bug.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityNobodyAssignedToBugType),
+		Message:   String("Nobody Assigned To Bug!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	assert.IsType(t, &ActivityNobodyAssignedToBug{}, actual.Activity())
+
 	actual.rawData = nil
 	actual.RawActor = nil
 	assert.Equal(t, expected, actual)
@@ -698,6 +969,27 @@ func Test_ActivityNotEligibleForBounty(t *testing.T) {
 		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 	}
+	assert.IsType(t, &ActivityNotEligibleForBounty{}, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ActivityProgramInactive(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-program-inactive.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityProgramInactiveType),
+		Message:   String("Program Inactive!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	assert.IsType(t, &ActivityProgramInactive{}, actual.Activity())
+
 	actual.rawData = nil
 	actual.RawActor = nil
 	assert.Equal(t, expected, actual)
@@ -722,15 +1014,10 @@ func Test_ActivityReferenceIDAdded(t *testing.T) {
 	}
 	assert.Equal(t, expectedActivity, actualActivity)
 
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Activity.Activity() with incorrect JSON should panic")
-			}
-		}()
-		actual.rawData = []byte(`{"attributes":123}`)
-		actual.Activity()
-	}()
+	actual.rawData = []byte(`{"attributes":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
 
 	actual.rawData = nil
 	actual.RawActor = nil
@@ -748,6 +1035,84 @@ func Test_ActivityReportBecamePublic(t *testing.T) {
 		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 	}
+	assert.IsType(t, &ActivityReportBecamePublic{}, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ActivityReportCollaboratorInvited(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-report-collaborator-invited.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityReportCollaboratorInvitedType),
+		Message:   String("Report Collaborator Invited!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	assert.IsType(t, &ActivityReportCollaboratorInvited{}, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ActivityReportCollaboratorJoined(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-report-collaborator-joined.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityReportCollaboratorJoinedType),
+		Message:   String("Report Collaborator Joined!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	assert.IsType(t, &ActivityReportCollaboratorJoined{}, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ActivityReportRetestApproved(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-report-retest-approved.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityReportRetestApprovedType),
+		Message:   String("Report Retest Approved!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	assert.IsType(t, &ActivityReportRetestApproved{}, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ActivityReportRetestRejected(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-report-retest-rejected.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityReportRetestRejectedType),
+		Message:   String("Report Retest Rejected!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	assert.IsType(t, &ActivityReportRetestRejected{}, actual.Activity())
+
 	actual.rawData = nil
 	actual.RawActor = nil
 	assert.Equal(t, expected, actual)
@@ -772,15 +1137,10 @@ func Test_ActivityReportTitleUpdated(t *testing.T) {
 	}
 	assert.Equal(t, expectedActivity, actualActivity)
 
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Activity.Activity() with incorrect JSON should panic")
-			}
-		}()
-		actual.rawData = []byte(`{"attributes":123}`)
-		actual.Activity()
-	}()
+	actual.rawData = []byte(`{"attributes":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
 
 	actual.rawData = nil
 	actual.RawActor = nil
@@ -802,8 +1162,8 @@ func Test_ActivityReportVulnerabilityTypesUpdated(t *testing.T) {
 
 	actualActivity := actual.Activity().(*ActivityReportVulnerabilityTypesUpdated)
 	expectedActivity := &ActivityReportVulnerabilityTypesUpdated{
-		OldVulnerabilityTypes: []VulnerabilityType{
-			VulnerabilityType{
+		OldVulnerabilityTypes: []Weakness{
+			Weakness{
 				ID:          String("1337"),
 				Type:        String(VulnerabilityTypeType),
 				Name:        String("Cross-Site Scripting (XSS)"),
@@ -811,8 +1171,8 @@ func Test_ActivityReportVulnerabilityTypesUpdated(t *testing.T) {
 				CreatedAt:   NewTimestamp("2016-02-02T04:05:06.000Z"),
 			},
 		},
-		NewVulnerabilityTypes: []VulnerabilityType{
-			VulnerabilityType{
+		NewVulnerabilityTypes: []Weakness{
+			Weakness{
 				ID:          String("1338"),
 				Type:        String(VulnerabilityTypeType),
 				Name:        String("UI Redressing (Clickjacking)"),
@@ -823,15 +1183,10 @@ func Test_ActivityReportVulnerabilityTypesUpdated(t *testing.T) {
 	}
 	assert.Equal(t, expectedActivity, actualActivity)
 
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Activity.Activity() with incorrect JSON should panic")
-			}
-		}()
-		actual.rawData = []byte(`{"relationships":123}`)
-		actual.Activity()
-	}()
+	actual.rawData = []byte(`{"relationships":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
 
 	actual.rawData = nil
 	actual.RawActor = nil
@@ -849,6 +1204,27 @@ func Test_ActivityReportSeverityUpdated(t *testing.T) {
 		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
 	}
+	assert.IsType(t, &ActivityReportSeverityUpdated{}, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ActivityRetestUserExpired(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-retest-user-expired.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityRetestUserExpiredType),
+		Message:   String("Retest User Expired!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	assert.IsType(t, &ActivityRetestUserExpired{}, actual.Activity())
+
 	actual.rawData = nil
 	actual.RawActor = nil
 	assert.Equal(t, expected, actual)
@@ -890,15 +1266,10 @@ func Test_ActivitySwagAwarded(t *testing.T) {
 	}
 	assert.Equal(t, expectedActivity, actualActivity)
 
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Activity.Activity() with incorrect JSON should panic")
-			}
-		}()
-		actual.rawData = []byte(`{"relationships":123}`)
-		actual.Activity()
-	}()
+	actual.rawData = []byte(`{"relationships":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
 
 	actual.rawData = nil
 	actual.RawActor = nil
@@ -936,15 +1307,10 @@ func Test_ActivityUserAssignedToBug(t *testing.T) {
 	}
 	assert.Equal(t, expectedActivity, actualActivity)
 
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Activity.Activity() with incorrect JSON should panic")
-			}
-		}()
-		actual.rawData = []byte(`{"relationships":123}`)
-		actual.Activity()
-	}()
+	actual.rawData = []byte(`{"relationships":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
 
 	actual.rawData = nil
 	actual.RawActor = nil
@@ -982,15 +1348,47 @@ func Test_ActivityUserBannedFromProgram(t *testing.T) {
 	}
 	assert.Equal(t, expectedActivity, actualActivity)
 
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Activity.Activity() with incorrect JSON should panic")
-			}
-		}()
-		actual.rawData = []byte(`{"relationships":123}`)
-		actual.Activity()
-	}()
+	actual.rawData = []byte(`{"relationships":123}`)
+	_, err := actual.ParseActivity()
+	assert.NotNil(t, err)
+	assert.Nil(t, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+func Test_ActivityUserCompletedRetest(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-user-completed-retest.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityUserCompletedRetestType),
+		Message:   String("User Completed Retest!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	assert.IsType(t, &ActivityUserCompletedRetest{}, actual.Activity())
+
+	actual.rawData = nil
+	actual.RawActor = nil
+	assert.Equal(t, expected, actual)
+}
+
+func Test_ActivityUserLeftRetest(t *testing.T) {
+	var actual Activity
+	loadResource(t, &actual, "tests/resources/activity-user-left-retest.json")
+	expected := Activity{
+		ID:        String("1337"),
+		Type:      String(ActivityUserLeftRetestType),
+		Message:   String("User Left Retest!"),
+		Internal:  Bool(false),
+		CreatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+		UpdatedAt: NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+
+	assert.IsType(t, &ActivityUserLeftRetest{}, actual.Activity())
 
 	actual.rawData = nil
 	actual.RawActor = nil
@@ -0,0 +1,78 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"fmt"
+	"math"
+)
+
+// AwardBounty represents a request body for awarding a bounty
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-award-bounty
+type AwardBounty struct {
+	Type        string  `jsonapi:"primary,bounty"`
+	Message     string  `jsonapi:"attr,message"`
+	Amount      float64 `jsonapi:"attr,amount"`
+	BonusAmount float64 `jsonapi:"attr,bonus_amount,omitempty"`
+}
+
+// SuggestBounty represents a request body for suggesting a bounty
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-suggest-bounty
+type SuggestBounty struct {
+	Type        string  `jsonapi:"primary,bounty-suggestion"`
+	Message     string  `jsonapi:"attr,message"`
+	Amount      float64 `jsonapi:"attr,amount,omitempty"`
+	BonusAmount float64 `jsonapi:"attr,bonus_amount,omitempty"`
+}
+
+// validateBountyAmounts checks that a bounty and its bonus are valid amounts and that at least one of them is set
+func validateBountyAmounts(amount, bonusAmount float64) error {
+	for _, field := range []struct {
+		parameter string
+		value     float64
+	}{
+		{"amount", amount},
+		{"bonus_amount", bonusAmount},
+	} {
+		if math.IsNaN(field.value) || math.IsInf(field.value, 0) || field.value < 0 {
+			return &ValidationError{
+				Parameter: field.parameter,
+				Detail:    fmt.Sprintf("%v is not a valid amount", field.value),
+			}
+		}
+		// Amounts are paid out in cents
+		if cents := field.value * 100; math.Abs(cents-math.Round(cents)) > 1e-6 {
+			return &ValidationError{
+				Parameter: field.parameter,
+				Detail:    fmt.Sprintf("%v has more than two decimals", field.value),
+			}
+		}
+	}
+	if amount == 0 && bonusAmount == 0 {
+		return &ValidationError{
+			Parameter: "amount",
+			Detail:    "either amount or bonus_amount must be greater than zero",
+		}
+	}
+	return nil
+}
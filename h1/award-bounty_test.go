@@ -0,0 +1,54 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"errors"
+	"math"
+	"testing"
+)
+
+func Test_validateBountyAmounts(t *testing.T) {
+	assert.Nil(t, validateBountyAmounts(500, 0))
+	assert.Nil(t, validateBountyAmounts(0, 50))
+	assert.Nil(t, validateBountyAmounts(0.29, 10.1))
+
+	for _, tc := range []struct {
+		amount, bonusAmount float64
+		parameter           string
+	}{
+		{0, 0, "amount"},
+		{-1, 0, "amount"},
+		{100, -1, "bonus_amount"},
+		{math.NaN(), 0, "amount"},
+		{math.Inf(1), 0, "amount"},
+		{100.001, 0, "amount"},
+		{100, 0.5001, "bonus_amount"},
+	} {
+		err := validateBountyAmounts(tc.amount, tc.bonusAmount)
+		var validationErr *ValidationError
+		if assert.True(t, errors.As(err, &validationErr), "%v %v", tc.amount, tc.bonusAmount) {
+			assert.Equal(t, tc.parameter, validationErr.Parameter)
+		}
+	}
+}
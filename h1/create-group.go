@@ -0,0 +1,77 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CreateGroup represents a request body for creating a group in a program
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#programs-create-group
+type CreateGroup struct {
+	Type        string   `jsonapi:"primary,group"`
+	Name        string   `jsonapi:"attr,name"`
+	Permissions []string `jsonapi:"attr,permissions"`
+}
+
+// UpdateGroup represents a request body for updating a group of a program
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#programs-update-group
+type UpdateGroup struct {
+	ID          string   `jsonapi:"primary,group"`
+	Name        string   `jsonapi:"attr,name"`
+	Permissions []string `jsonapi:"attr,permissions"`
+}
+
+// AddGroupMember represents a request body for adding a member to a group of a program
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#programs-add-group-member
+type AddGroupMember struct {
+	ID string `jsonapi:"primary,member"`
+}
+
+// validateGroup checks that a group has a name and that its permissions are GroupPermission values without duplicates
+func validateGroup(name string, permissions []string) error {
+	if strings.TrimSpace(name) == "" {
+		return &ValidationError{Parameter: "name", Detail: "group name must not be empty"}
+	}
+	seen := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		switch permission {
+		case GroupPermissionRewardManagement, GroupPermissionProgramManagement, GroupPermissionUserManagement, GroupPermissionReportManagement:
+		default:
+			return &ValidationError{
+				Parameter: "permissions",
+				Detail:    fmt.Sprintf("%q is not a valid group permission", permission),
+			}
+		}
+		if seen[permission] {
+			return &ValidationError{
+				Parameter: "permissions",
+				Detail:    fmt.Sprintf("group permission %q is given more than once", permission),
+			}
+		}
+		seen[permission] = true
+	}
+	return nil
+}
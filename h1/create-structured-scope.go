@@ -0,0 +1,73 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"strings"
+)
+
+// CreateStructuredScope represents a request body for creating a structured scope in a program
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#programs-create-structured-scope
+type CreateStructuredScope struct {
+	Type                       string  `jsonapi:"primary,structured-scope"`
+	AssetIdentifier            string  `jsonapi:"attr,asset_identifier"`
+	AssetType                  string  `jsonapi:"attr,asset_type"`
+	EligibleForBounty          bool    `jsonapi:"attr,eligible_for_bounty"`
+	EligibleForSubmission      bool    `jsonapi:"attr,eligible_for_submission"`
+	Instruction                *string `jsonapi:"attr,instruction,omitempty"`
+	ConfidentialityRequirement *string `jsonapi:"attr,confidentiality_requirement,omitempty"`
+	IntegrityRequirement       *string `jsonapi:"attr,integrity_requirement,omitempty"`
+	AvailabilityRequirement    *string `jsonapi:"attr,availability_requirement,omitempty"`
+	MaxSeverity                string  `jsonapi:"attr,max_severity,omitempty"`
+	Reference                  *string `jsonapi:"attr,reference,omitempty"`
+}
+
+// UpdateProgramStructuredScope represents a request body for updating a structured scope of a program
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#programs-update-structured-scope
+type UpdateProgramStructuredScope struct {
+	ID                         string  `jsonapi:"primary,structured-scope"`
+	AssetIdentifier            string  `jsonapi:"attr,asset_identifier"`
+	AssetType                  string  `jsonapi:"attr,asset_type"`
+	EligibleForBounty          bool    `jsonapi:"attr,eligible_for_bounty"`
+	EligibleForSubmission      bool    `jsonapi:"attr,eligible_for_submission"`
+	Instruction                *string `jsonapi:"attr,instruction,omitempty"`
+	ConfidentialityRequirement *string `jsonapi:"attr,confidentiality_requirement,omitempty"`
+	IntegrityRequirement       *string `jsonapi:"attr,integrity_requirement,omitempty"`
+	AvailabilityRequirement    *string `jsonapi:"attr,availability_requirement,omitempty"`
+	MaxSeverity                string  `jsonapi:"attr,max_severity,omitempty"`
+	Reference                  *string `jsonapi:"attr,reference,omitempty"`
+}
+
+// validateStructuredScope checks that a structured scope identifies an asset
+func validateStructuredScope(scope *StructuredScope) error {
+	if scope == nil {
+		return &ValidationError{Parameter: "structured_scope", Detail: "missing structured scope"}
+	}
+	if strings.TrimSpace(scope.AssetIdentifier) == "" {
+		return &ValidationError{Parameter: "asset_identifier", Detail: "structured scope asset identifier must not be empty"}
+	}
+	if strings.TrimSpace(scope.AssetType) == "" {
+		return &ValidationError{Parameter: "asset_type", Detail: "structured scope asset type must not be empty"}
+	}
+	return nil
+}
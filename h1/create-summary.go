@@ -0,0 +1,63 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CreateSummary represents a request body for creating a report summary
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-create-summary
+type CreateSummary struct {
+	Type     string `jsonapi:"primary,report-summary"`
+	Content  string `jsonapi:"attr,content"`
+	Category string `jsonapi:"attr,category"`
+}
+
+// UpdateSummary represents a request body for updating a report summary
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-update-summary
+type UpdateSummary struct {
+	ID       string `jsonapi:"primary,report-summary"`
+	Content  string `jsonapi:"attr,content"`
+	Category string `jsonapi:"attr,category"`
+}
+
+// validateSummary checks that a summary has content and one of the ReportSummaryCategory values
+func validateSummary(category, content string) error {
+	switch category {
+	case ReportSummaryCategoryResearcher, ReportSummaryCategoryTeam:
+	default:
+		return &ValidationError{
+			Parameter: "category",
+			Detail:    fmt.Sprintf("%q is not a valid summary category", category),
+		}
+	}
+	if strings.TrimSpace(content) == "" {
+		return &ValidationError{
+			Parameter: "content",
+			Detail:    "summary content must not be empty",
+		}
+	}
+	return nil
+}
@@ -22,6 +22,7 @@ package h1
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -32,11 +33,15 @@ import (
 type CredentialService service
 
 func (s *CredentialService) ListCredentialInquiries(programID string, listOpts *ListOptions) ([]CredentialInquiry, *Response, error) {
+	return s.ListCredentialInquiriesWithContext(context.Background(), programID, listOpts)
+}
+
+func (s *CredentialService) ListCredentialInquiriesWithContext(ctx context.Context, programID string, listOpts *ListOptions) ([]CredentialInquiry, *Response, error) {
 	opts := struct{}{}
 	// addOptions takes structs only so it can't fail
 	u, _ := addOptions(fmt.Sprintf("programs/%s/credential_inquiries", programID), &opts, listOpts)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -50,29 +55,34 @@ func (s *CredentialService) ListCredentialInquiries(programID string, listOpts *
 	return *inquiries, resp, err
 }
 
+func (s *CredentialService) ListCredentialInquiriesIter(programID string, opts *IteratorOptions) *Iterator[CredentialInquiry] {
+	return s.ListCredentialInquiriesIterWithContext(context.Background(), programID, opts)
+}
+
+func (s *CredentialService) ListCredentialInquiriesIterWithContext(ctx context.Context, programID string, opts *IteratorOptions) *Iterator[CredentialInquiry] {
+	return newIterator(ctx, s.client, func(ctx context.Context, listOpts *ListOptions) ([]CredentialInquiry, *Response, error) {
+		return s.ListCredentialInquiriesWithContext(ctx, programID, listOpts)
+	}, opts)
+}
+
 func (s *CredentialService) ListAllCredentialInquiries(programID string) ([]CredentialInquiry, *Response, error) {
-	listOpts := &ListOptions{PageSize: defaultPageSize}
-	data := []CredentialInquiry{}
-	for {
-		items, resp, err := s.ListCredentialInquiries(programID, listOpts)
-		if err != nil {
-			return nil, resp, err
-		}
-		data = append(data, items...)
-		if resp.Links.Next == "" {
-			break
-		}
-		listOpts.Page = resp.Links.NextPageNumber()
-	}
-	return data, nil, nil
+	return s.ListAllCredentialInquiriesWithContext(context.Background(), programID)
+}
+
+func (s *CredentialService) ListAllCredentialInquiriesWithContext(ctx context.Context, programID string) ([]CredentialInquiry, *Response, error) {
+	return collect(s.ListCredentialInquiriesIterWithContext(ctx, programID, nil))
 }
 
 func (s *CredentialService) ListCredentialInquiryResponses(programID, inquiryID string, listOpts *ListOptions) ([]CredentialInquiryResponse, *Response, error) {
+	return s.ListCredentialInquiryResponsesWithContext(context.Background(), programID, inquiryID, listOpts)
+}
+
+func (s *CredentialService) ListCredentialInquiryResponsesWithContext(ctx context.Context, programID, inquiryID string, listOpts *ListOptions) ([]CredentialInquiryResponse, *Response, error) {
 	opts := struct{}{}
 	// addOptions takes structs only so it can't fail
 	u, _ := addOptions(fmt.Sprintf("programs/%s/credential_inquiries/%s/credential_inquiry_responses", programID, inquiryID), &opts, listOpts)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -86,27 +96,35 @@ func (s *CredentialService) ListCredentialInquiryResponses(programID, inquiryID
 	return *responses, resp, err
 }
 
+func (s *CredentialService) ListCredentialInquiryResponsesIter(programID, inquiryID string, opts *IteratorOptions) *Iterator[CredentialInquiryResponse] {
+	return s.ListCredentialInquiryResponsesIterWithContext(context.Background(), programID, inquiryID, opts)
+}
+
+func (s *CredentialService) ListCredentialInquiryResponsesIterWithContext(ctx context.Context, programID, inquiryID string, opts *IteratorOptions) *Iterator[CredentialInquiryResponse] {
+	return newIterator(ctx, s.client, func(ctx context.Context, listOpts *ListOptions) ([]CredentialInquiryResponse, *Response, error) {
+		return s.ListCredentialInquiryResponsesWithContext(ctx, programID, inquiryID, listOpts)
+	}, opts)
+}
+
 func (s *CredentialService) ListAllCredentialInquiryResponses(programID, inquiryID string) ([]CredentialInquiryResponse, *Response, error) {
-	listOpts := &ListOptions{PageSize: defaultPageSize}
-	data := []CredentialInquiryResponse{}
-	for {
-		items, resp, err := s.ListCredentialInquiryResponses(programID, inquiryID, listOpts)
-		if err != nil {
-			return nil, resp, err
-		}
-		data = append(data, items...)
-		if resp.Links.Next == "" {
-			break
-		}
-		listOpts.Page = resp.Links.NextPageNumber()
-	}
-	return data, nil, nil
+	return s.ListAllCredentialInquiryResponsesWithContext(context.Background(), programID, inquiryID)
+}
+
+func (s *CredentialService) ListAllCredentialInquiryResponsesWithContext(ctx context.Context, programID, inquiryID string) ([]CredentialInquiryResponse, *Response, error) {
+	return collect(s.ListCredentialInquiryResponsesIterWithContext(ctx, programID, inquiryID, nil))
 }
 
 // CreateCredential creates a new credential for specified StructuredScope
 //
 // HackerOne API docs: https://api.hackerone.com/customer-resources/#credentials-create-a-credential
 func (s *CredentialService) CreateCredential(structuredScopeID string, credentials interface{}, assignee string) (*Credential, *Response, error) {
+	return s.CreateCredentialWithContext(context.Background(), structuredScopeID, credentials, assignee)
+}
+
+// CreateCredentialWithContext creates a new credential for specified StructuredScope using the provided context
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#credentials-create-a-credential
+func (s *CredentialService) CreateCredentialWithContext(ctx context.Context, structuredScopeID string, credentials interface{}, assignee string) (*Credential, *Response, error) {
 	b, err := json.Marshal(credentials)
 	if err != nil {
 		return nil, nil, err
@@ -132,7 +150,7 @@ func (s *CredentialService) CreateCredential(structuredScopeID string, credentia
 	if err := json.NewEncoder(body).Encode(credential); err != nil {
 		return nil, nil, err
 	}
-	req, err := http.NewRequest("POST", s.client.BaseURL.ResolveReference(rel).String(), body)
+	req, err := http.NewRequestWithContext(ctx, "POST", s.client.BaseURL.ResolveReference(rel).String(), body)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -149,7 +167,11 @@ func (s *CredentialService) CreateCredential(structuredScopeID string, credentia
 }
 
 func (s *CredentialService) DeleteCredential(ID string) (*Response, error) {
-	req, err := s.client.NewRequest("DELETE", fmt.Sprintf("credentials/%s", ID), nil)
+	return s.DeleteCredentialWithContext(context.Background(), ID)
+}
+
+func (s *CredentialService) DeleteCredentialWithContext(ctx context.Context, ID string) (*Response, error) {
+	req, err := s.client.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("credentials/%s", ID), nil)
 	if err != nil {
 		return nil, err
 	}
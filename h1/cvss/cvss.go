@@ -0,0 +1,206 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package cvss parses, formats and scores CVSS vectors, and converts them to and from h1.Severity.
+//
+// CVSS v3.0 and v3.1 vectors are scored with the formulas of their specification. CVSS v4.0 vectors are scored
+// with the MacroVector lookup and interpolation of the v4.0 specification, in which threat metrics take the role
+// of the v3 temporal metrics.
+package cvss
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CVSS versions supported by this package
+const (
+	Version30 string = "3.0"
+	Version31 string = "3.1"
+	Version40 string = "4.0"
+)
+
+// NotDefined is the value of optional metrics which are absent from a vector
+const NotDefined string = "X"
+
+// metricDefinition describes a metric of a CVSS version
+type metricDefinition struct {
+	key       string
+	values    []string
+	mandatory bool
+}
+
+// allows returns whether value is a valid value for the metric
+func (d metricDefinition) allows(value string) bool {
+	for _, v := range d.values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// definitions returns the metrics of a CVSS version in their canonical order
+func definitions(version string) ([]metricDefinition, error) {
+	switch version {
+	case Version30, Version31:
+		return v3Metrics, nil
+	case Version40:
+		return v4Metrics, nil
+	}
+	return nil, fmt.Errorf("cvss: unknown version %q", version)
+}
+
+// Vector represents a CVSS vector. Metrics are identified by their abbreviation, such as "AV",
+// and hold abbreviated values, such as "N".
+type Vector struct {
+	version string
+	metrics map[string]string
+}
+
+// New returns a vector of the given version without any metric set. Mandatory metrics must be set with
+// SetMetric before it can be formatted or scored.
+func New(version string) (*Vector, error) {
+	if _, err := definitions(version); err != nil {
+		return nil, err
+	}
+	return &Vector{version: version, metrics: map[string]string{}}, nil
+}
+
+// Parse parses a vector string such as "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H".
+// Metrics may appear in any order, but each of them only once, and all mandatory metrics must be present.
+func Parse(vector string) (*Vector, error) {
+	parts := strings.Split(vector, "/")
+	if !strings.HasPrefix(parts[0], "CVSS:") {
+		return nil, fmt.Errorf("cvss: missing version prefix in %q", vector)
+	}
+	v, err := New(strings.TrimPrefix(parts[0], "CVSS:"))
+	if err != nil {
+		return nil, err
+	}
+	for _, part := range parts[1:] {
+		kv := strings.SplitN(part, ":", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("cvss: malformed metric %q", part)
+		}
+		if _, ok := v.metrics[kv[0]]; ok {
+			return nil, fmt.Errorf("cvss: duplicate metric %q", kv[0])
+		}
+		if err := v.SetMetric(kv[0], kv[1]); err != nil {
+			return nil, err
+		}
+	}
+	if err := v.validate(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Version returns the CVSS version of the vector, such as Version31
+func (v *Vector) Version() string {
+	return v.version
+}
+
+// Metric returns the value of a metric. Optional metrics which are not set are NotDefined.
+func (v *Vector) Metric(key string) string {
+	if value, ok := v.metrics[key]; ok {
+		return value
+	}
+	return NotDefined
+}
+
+// SetMetric sets the value of a metric. Setting an optional metric to NotDefined removes it from the vector.
+func (v *Vector) SetMetric(key, value string) error {
+	defs, _ := definitions(v.version)
+	for _, def := range defs {
+		if def.key != key {
+			continue
+		}
+		if value == NotDefined && !def.mandatory {
+			delete(v.metrics, key)
+			return nil
+		}
+		if !def.allows(value) {
+			return fmt.Errorf("cvss: invalid value %q for metric %q", value, key)
+		}
+		v.metrics[key] = value
+		return nil
+	}
+	return fmt.Errorf("cvss: unknown metric %q for version %s", key, v.version)
+}
+
+// validate checks that all mandatory metrics are set
+func (v *Vector) validate() error {
+	defs, _ := definitions(v.version)
+	for _, def := range defs {
+		if _, ok := v.metrics[def.key]; def.mandatory && !ok {
+			return fmt.Errorf("cvss: missing mandatory metric %q", def.key)
+		}
+	}
+	return nil
+}
+
+// String formats the vector with its metrics in canonical order. Optional metrics which are not set are omitted.
+func (v *Vector) String() string {
+	defs, _ := definitions(v.version)
+	var b strings.Builder
+	b.WriteString("CVSS:" + v.version)
+	for _, def := range defs {
+		if value, ok := v.metrics[def.key]; ok {
+			b.WriteString("/" + def.key + ":" + value)
+		}
+	}
+	return b.String()
+}
+
+// BaseScore returns the base score of the vector
+func (v *Vector) BaseScore() (float64, error) {
+	if err := v.validate(); err != nil {
+		return 0, err
+	}
+	if v.version == Version40 {
+		return v.v4Score(false, false), nil
+	}
+	return v.v3Score(1), nil
+}
+
+// TemporalScore returns the base score of the vector adjusted by its temporal metrics. For CVSS v4.0 vectors,
+// this is the CVSS-BT score adjusted by the threat metrics.
+func (v *Vector) TemporalScore() (float64, error) {
+	if err := v.validate(); err != nil {
+		return 0, err
+	}
+	if v.version == Version40 {
+		return v.v4Score(true, false), nil
+	}
+	return v.v3Score(v.temporal()), nil
+}
+
+// EnvironmentalScore returns the score of the vector adjusted by its temporal and environmental metrics.
+// For CVSS v4.0 vectors, this is the CVSS-BTE score adjusted by the threat and environmental metrics.
+func (v *Vector) EnvironmentalScore() (float64, error) {
+	if err := v.validate(); err != nil {
+		return 0, err
+	}
+	if v.version == Version40 {
+		return v.v4Score(true, true), nil
+	}
+	return v.v3EnvironmentalScore(), nil
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cvss
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"testing"
+)
+
+func Test_Parse(t *testing.T) {
+	// Verify that vectors are formatted in canonical order without undefined metrics
+	v, err := Parse("CVSS:3.1/S:U/AV:N/AC:L/PR:N/UI:N/C:H/I:H/A:H/E:X/RC:C")
+	require.Nil(t, err)
+	assert.Equal(t, Version31, v.Version())
+	assert.Equal(t, "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/RC:C", v.String())
+	assert.Equal(t, "N", v.Metric("AV"))
+	assert.Equal(t, NotDefined, v.Metric("E"))
+
+	// Verify that CVSS v4.0 vectors round trip
+	v4 := "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:A/MSI:S/U:Red"
+	v, err = Parse(v4)
+	require.Nil(t, err)
+	assert.Equal(t, v4, v.String())
+	score, err := v.BaseScore()
+	require.Nil(t, err)
+	assert.Equal(t, 9.3, score)
+
+	// Verify that malformed vectors are rejected
+	for _, vector := range []string{
+		"",
+		"AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		"CVSS:2.0/AV:N/AC:L/Au:N/C:P/I:P/A:P",
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H",
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/A:L",
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:X",
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/AT:N",
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/",
+		"CVSS:3.1/AV:Z/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		"CVSS:4.0/AV:N/AC:L/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+	} {
+		_, err := Parse(vector)
+		assert.NotNil(t, err, vector)
+	}
+}
+
+func Test_Vector_SetMetric(t *testing.T) {
+	v, err := New(Version31)
+	require.Nil(t, err)
+	_, err = v.BaseScore()
+	assert.NotNil(t, err)
+
+	for _, metric := range []string{"AV:N", "AC:L", "PR:N", "UI:N", "S:U", "C:H", "I:N", "A:N", "E:P"} {
+		require.Nil(t, v.SetMetric(metric[:len(metric)-2], metric[len(metric)-1:]))
+	}
+	assert.Equal(t, "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N/E:P", v.String())
+	assert.Nil(t, v.SetMetric("E", NotDefined))
+	assert.Equal(t, "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N", v.String())
+
+	assert.NotNil(t, v.SetMetric("AV", NotDefined))
+	assert.NotNil(t, v.SetMetric("XX", "N"))
+	_, err = New("2.0")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cvss
+
+import (
+	"fmt"
+
+	"github.com/uber-go/hackeroni/h1"
+)
+
+// severityValues maps the CVSS v3 metric values used by h1.Severity to their abbreviations
+var severityValues = map[string]map[string]string{
+	"AV": {h1.SeverityAttackVectorNetwork: "N", h1.SeverityAttackVectorAdjacent: "A", h1.SeverityAttackVectorLocal: "L", h1.SeverityAttackVectorPhysical: "P"},
+	"AC": {h1.SeverityAttackComplexityLow: "L", h1.SeverityAttackComplexityHigh: "H"},
+	"PR": {h1.SeverityPrivilegesRequiredNone: "N", h1.SeverityPrivilegesRequiredLow: "L", h1.SeverityPrivilegesRequiredHigh: "H"},
+	"UI": {h1.SeverityUserInteractionNone: "N", h1.SeverityUserInteractionRequired: "R"},
+	"S":  {h1.SeverityScopeUnchanged: "U", h1.SeverityScopeChanged: "C"},
+	"C":  {h1.SeverityConfidentialityNone: "N", h1.SeverityConfidentialityLow: "L", h1.SeverityConfidentialityHigh: "H"},
+	"I":  {h1.SeverityIntegrityNone: "N", h1.SeverityIntegrityLow: "L", h1.SeverityIntegrityHigh: "H"},
+	"A":  {h1.SeverityAvailabilityNone: "N", h1.SeverityAvailabilityLow: "L", h1.SeverityAvailabilityHigh: "H"},
+}
+
+// severityMetrics returns pointers to the CVSS v3 metrics of s keyed by their abbreviation
+func severityMetrics(s *h1.Severity) map[string]**string {
+	return map[string]**string{
+		"AV": &s.AttackVector,
+		"AC": &s.AttackComplexity,
+		"PR": &s.PrivilegesRequired,
+		"UI": &s.UserInteraction,
+		"S":  &s.Scope,
+		"C":  &s.Confidentiality,
+		"I":  &s.Integrity,
+		"A":  &s.Availability,
+	}
+}
+
+// requirementValues maps the requirements of h1.StructuredScope to their abbreviations. CVSS has no weight
+// below low, so a requirement of none is scored as low.
+var requirementValues = map[string]string{
+	"none":   "L",
+	"low":    "L",
+	"medium": "M",
+	"high":   "H",
+}
+
+// Rating returns the h1.SeverityRating* constant matching a CVSS score
+func Rating(score float64) string {
+	switch {
+	case score >= 9:
+		return h1.SeverityRatingCritical
+	case score >= 7:
+		return h1.SeverityRatingHigh
+	case score >= 4:
+		return h1.SeverityRatingMedium
+	case score > 0:
+		return h1.SeverityRatingLow
+	}
+	return h1.SeverityRatingNone
+}
+
+// FromSeverity returns the CVSS v3.1 vector of the metrics of a h1.Severity. All metrics must be set.
+func FromSeverity(s *h1.Severity) (*Vector, error) {
+	v, _ := New(Version31)
+	for key, field := range severityMetrics(s) {
+		if *field == nil {
+			return nil, fmt.Errorf("cvss: severity has no value for metric %q", key)
+		}
+		value, ok := severityValues[key][**field]
+		if !ok {
+			return nil, fmt.Errorf("cvss: invalid severity value %q for metric %q", **field, key)
+		}
+		v.metrics[key] = value
+	}
+	return v, nil
+}
+
+// Severity returns a h1.Severity with the base score and matching rating of a vector. The base metrics are only
+// set for CVSS v3 vectors, since h1.Severity has no fields for the CVSS v4.0 ones. It can be passed to
+// ReportService.UpdateSeverity.
+func (v *Vector) Severity() (*h1.Severity, error) {
+	score, err := v.BaseScore()
+	if err != nil {
+		return nil, err
+	}
+	s := &h1.Severity{
+		Rating: h1.String(Rating(score)),
+		Score:  h1.Float64(score),
+	}
+	if v.version == Version40 {
+		return s, nil
+	}
+	for key, field := range severityMetrics(s) {
+		for name, value := range severityValues[key] {
+			if value == v.Metric(key) {
+				*field = h1.String(name)
+			}
+		}
+	}
+	return s, nil
+}
+
+// WithRequirements returns a copy of the vector with its confidentiality, integrity and availability
+// requirements set from a h1.StructuredScope, so that EnvironmentalScore reflects the importance of the asset.
+// Requirements which the structured scope does not define are left untouched.
+func (v *Vector) WithRequirements(scope *h1.StructuredScope) (*Vector, error) {
+	c := &Vector{version: v.version, metrics: map[string]string{}}
+	for key, value := range v.metrics {
+		c.metrics[key] = value
+	}
+	for key, requirement := range map[string]*string{
+		"CR": scope.ConfidentialityRequirement,
+		"IR": scope.IntegrityRequirement,
+		"AR": scope.AvailabilityRequirement,
+	} {
+		if requirement == nil || *requirement == "" {
+			continue
+		}
+		value, ok := requirementValues[*requirement]
+		if !ok {
+			return nil, fmt.Errorf("cvss: invalid requirement %q for metric %q", *requirement, key)
+		}
+		if err := c.SetMetric(key, value); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cvss
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"testing"
+
+	"github.com/uber-go/hackeroni/h1"
+)
+
+func Test_Rating(t *testing.T) {
+	assert.Equal(t, h1.SeverityRatingNone, Rating(0))
+	assert.Equal(t, h1.SeverityRatingLow, Rating(0.1))
+	assert.Equal(t, h1.SeverityRatingLow, Rating(3.9))
+	assert.Equal(t, h1.SeverityRatingMedium, Rating(4.0))
+	assert.Equal(t, h1.SeverityRatingMedium, Rating(6.9))
+	assert.Equal(t, h1.SeverityRatingHigh, Rating(7.0))
+	assert.Equal(t, h1.SeverityRatingHigh, Rating(8.9))
+	assert.Equal(t, h1.SeverityRatingCritical, Rating(9.0))
+	assert.Equal(t, h1.SeverityRatingCritical, Rating(10.0))
+}
+
+func Test_Severity(t *testing.T) {
+	severity := &h1.Severity{
+		AttackVector:       h1.String(h1.SeverityAttackVectorAdjacent),
+		AttackComplexity:   h1.String(h1.SeverityAttackComplexityLow),
+		PrivilegesRequired: h1.String(h1.SeverityPrivilegesRequiredLow),
+		UserInteraction:    h1.String(h1.SeverityUserInteractionRequired),
+		Scope:              h1.String(h1.SeverityScopeChanged),
+		Confidentiality:    h1.String(h1.SeverityConfidentialityLow),
+		Integrity:          h1.String(h1.SeverityIntegrityHigh),
+		Availability:       h1.String(h1.SeverityAvailabilityHigh),
+	}
+
+	// Verify that a severity converts to a vector
+	v, err := FromSeverity(severity)
+	require.Nil(t, err)
+	assert.Equal(t, "CVSS:3.1/AV:A/AC:L/PR:L/UI:R/S:C/C:L/I:H/A:H", v.String())
+
+	// Verify that the vector converts back with its score and rating
+	actual, err := v.Severity()
+	require.Nil(t, err)
+	expected := *severity
+	expected.Score = h1.Float64(8.3)
+	expected.Rating = h1.String(h1.SeverityRatingHigh)
+	assert.Equal(t, &expected, actual)
+	assert.Nil(t, actual.Validate())
+
+	// Verify that incomplete and invalid severities are rejected
+	incomplete := *severity
+	incomplete.Scope = nil
+	_, err = FromSeverity(&incomplete)
+	assert.NotNil(t, err)
+	invalid := *severity
+	invalid.Scope = h1.String("global")
+	_, err = FromSeverity(&invalid)
+	assert.NotNil(t, err)
+
+	// Verify that CVSS v4.0 vectors only set the score and rating
+	v, err = Parse("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N")
+	require.Nil(t, err)
+	actual, err = v.Severity()
+	require.Nil(t, err)
+	assert.Equal(t, &h1.Severity{Rating: h1.String(h1.SeverityRatingCritical), Score: h1.Float64(9.3)}, actual)
+}
+
+func Test_Vector_WithRequirements(t *testing.T) {
+	v, err := Parse("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H")
+	require.Nil(t, err)
+
+	// Verify that the requirements of the structured scope are applied to a copy
+	scoped, err := v.WithRequirements(&h1.StructuredScope{
+		ConfidentialityRequirement: h1.String("low"),
+		IntegrityRequirement:       h1.String("none"),
+		AvailabilityRequirement:    h1.String("low"),
+	})
+	require.Nil(t, err)
+	assert.Equal(t, "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/CR:L/IR:L/AR:L", scoped.String())
+	assert.Equal(t, "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", v.String())
+	score, err := scoped.EnvironmentalScore()
+	assert.Nil(t, err)
+	assert.Equal(t, 8.0, score)
+
+	// Verify that missing requirements are left untouched
+	scoped, err = v.WithRequirements(&h1.StructuredScope{ConfidentialityRequirement: h1.String("high")})
+	require.Nil(t, err)
+	assert.Equal(t, "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/CR:H", scoped.String())
+
+	// Verify that unknown requirements are rejected
+	_, err = v.WithRequirements(&h1.StructuredScope{IntegrityRequirement: h1.String("extreme")})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cvss
+
+import (
+	"math"
+)
+
+// v3Metrics are the metrics of CVSS v3.0 and v3.1
+//
+// Specification: https://www.first.org/cvss/v3.1/specification-document
+var v3Metrics = []metricDefinition{
+	// Base
+	{"AV", []string{"N", "A", "L", "P"}, true},
+	{"AC", []string{"L", "H"}, true},
+	{"PR", []string{"N", "L", "H"}, true},
+	{"UI", []string{"N", "R"}, true},
+	{"S", []string{"U", "C"}, true},
+	{"C", []string{"H", "L", "N"}, true},
+	{"I", []string{"H", "L", "N"}, true},
+	{"A", []string{"H", "L", "N"}, true},
+	// Temporal
+	{"E", []string{"X", "H", "F", "P", "U"}, false},
+	{"RL", []string{"X", "U", "W", "T", "O"}, false},
+	{"RC", []string{"X", "C", "R", "U"}, false},
+	// Environmental
+	{"CR", []string{"X", "H", "M", "L"}, false},
+	{"IR", []string{"X", "H", "M", "L"}, false},
+	{"AR", []string{"X", "H", "M", "L"}, false},
+	{"MAV", []string{"X", "N", "A", "L", "P"}, false},
+	{"MAC", []string{"X", "L", "H"}, false},
+	{"MPR", []string{"X", "N", "L", "H"}, false},
+	{"MUI", []string{"X", "N", "R"}, false},
+	{"MS", []string{"X", "U", "C"}, false},
+	{"MC", []string{"X", "H", "L", "N"}, false},
+	{"MI", []string{"X", "H", "L", "N"}, false},
+	{"MA", []string{"X", "H", "L", "N"}, false},
+}
+
+// v3Weights are the numerical values of the CVSS v3 metrics, except for privileges required which depend on scope
+var v3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+	"E":  {"X": 1, "H": 1, "F": 0.97, "P": 0.94, "U": 0.91},
+	"RL": {"X": 1, "U": 1, "W": 0.97, "T": 0.96, "O": 0.95},
+	"RC": {"X": 1, "C": 1, "R": 0.96, "U": 0.92},
+	"CR": {"X": 1, "H": 1.5, "M": 1, "L": 0.5},
+	"IR": {"X": 1, "H": 1.5, "M": 1, "L": 0.5},
+	"AR": {"X": 1, "H": 1.5, "M": 1, "L": 0.5},
+}
+
+// v3PrivilegesRequired returns the weight of privileges required, which is higher when the scope changes
+func v3PrivilegesRequired(value string, scopeChanged bool) float64 {
+	switch value {
+	case "L":
+		if scopeChanged {
+			return 0.68
+		}
+		return 0.62
+	case "H":
+		if scopeChanged {
+			return 0.5
+		}
+		return 0.27
+	}
+	return 0.85
+}
+
+// roundUp returns the smallest number with one decimal that is equal to or higher than its input.
+// CVSS v3.1 avoids floating point inaccuracies by working on integers, while v3.0 uses a plain ceiling.
+func (v *Vector) roundUp(x float64) float64 {
+	if v.version == Version30 {
+		return math.Ceil(x*10) / 10
+	}
+	i := math.Round(x * 100000)
+	if math.Mod(i, 10000) == 0 {
+		return i / 100000
+	}
+	return (math.Floor(i/10000) + 1) / 10
+}
+
+// modified returns the value of the modified version of a base metric, falling back to the base metric
+func (v *Vector) modified(key string) string {
+	if value := v.Metric("M" + key); value != NotDefined {
+		return value
+	}
+	return v.Metric(key)
+}
+
+// v3Score computes the base score of the vector multiplied by the given temporal factor
+func (v *Vector) v3Score(temporal float64) float64 {
+	scopeChanged := v.Metric("S") == "C"
+	iss := 1 - (1-v3Weights["C"][v.Metric("C")])*(1-v3Weights["I"][v.Metric("I")])*(1-v3Weights["A"][v.Metric("A")])
+	exploitability := 8.22 * v3Weights["AV"][v.Metric("AV")] * v3Weights["AC"][v.Metric("AC")] *
+		v3PrivilegesRequired(v.Metric("PR"), scopeChanged) * v3Weights["UI"][v.Metric("UI")]
+
+	var impact float64
+	if scopeChanged {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	} else {
+		impact = 6.42 * iss
+	}
+	if impact <= 0 {
+		return 0
+	}
+	if scopeChanged {
+		return v.roundUp(v.roundUp(math.Min(1.08*(impact+exploitability), 10)) * temporal)
+	}
+	return v.roundUp(v.roundUp(math.Min(impact+exploitability, 10)) * temporal)
+}
+
+// temporal returns the product of the temporal metric weights
+func (v *Vector) temporal() float64 {
+	return v3Weights["E"][v.Metric("E")] * v3Weights["RL"][v.Metric("RL")] * v3Weights["RC"][v.Metric("RC")]
+}
+
+// v3EnvironmentalScore computes the score of the vector adjusted by its temporal and environmental metrics
+func (v *Vector) v3EnvironmentalScore() float64 {
+	requirements := func(key string) float64 { return v3Weights[key+"R"][v.Metric(key+"R")] }
+	scopeChanged := v.modified("S") == "C"
+
+	// The modified impact caps the impact sub score and uses a different formula when the scope changes
+	miss := math.Min(1-(1-requirements("C")*v3Weights["C"][v.modified("C")])*
+		(1-requirements("I")*v3Weights["I"][v.modified("I")])*
+		(1-requirements("A")*v3Weights["A"][v.modified("A")]), 0.915)
+	exploitability := 8.22 * v3Weights["AV"][v.modified("AV")] * v3Weights["AC"][v.modified("AC")] *
+		v3PrivilegesRequired(v.modified("PR"), scopeChanged) * v3Weights["UI"][v.modified("UI")]
+
+	var impact float64
+	if scopeChanged {
+		if v.version == Version30 {
+			impact = 7.52*(miss-0.029) - 3.25*math.Pow(miss-0.02, 15)
+		} else {
+			impact = 7.52*(miss-0.029) - 3.25*math.Pow(miss*0.9731-0.02, 13)
+		}
+	} else {
+		impact = 6.42 * miss
+	}
+	if impact <= 0 {
+		return 0
+	}
+	if scopeChanged {
+		return v.roundUp(v.roundUp(math.Min(1.08*(impact+exploitability), 10)) * v.temporal())
+	}
+	return v.roundUp(v.roundUp(math.Min(impact+exploitability, 10)) * v.temporal())
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cvss
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"testing"
+)
+
+func Test_Vector_Scores(t *testing.T) {
+	// Examples of the CVSS v3.1 specification (https://www.first.org/cvss/v3.1/examples)
+	for _, tc := range []struct {
+		vector                        string
+		base, temporal, environmental float64
+	}{
+		// CVE-2013-1937 (phpMyAdmin reflected XSS)
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", 6.1, 6.1, 6.1},
+		// CVE-2013-0375 (MySQL stored SQL injection)
+		{"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:L/I:L/A:N", 6.4, 6.4, 6.4},
+		// CVE-2014-3566 (SSLv3 POODLE)
+		{"CVSS:3.1/AV:N/AC:H/PR:N/UI:R/S:U/C:L/I:N/A:N", 3.1, 3.1, 3.1},
+		// CVE-2012-1516 (VMware guest to host escape). The v3.1 modified impact formula differs from the base one
+		// when the scope changes, so the environmental score can be higher than the base score.
+		{"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H", 9.9, 9.9, 10.0},
+		// CVE-2009-0783 (Apache Tomcat XML parser)
+		{"CVSS:3.1/AV:L/AC:L/PR:H/UI:N/S:U/C:L/I:L/A:L", 4.2, 4.2, 4.2},
+		// CVE-2012-0384 (Cisco IOS arbitrary command execution)
+		{"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:U/C:H/I:H/A:H", 8.8, 8.8, 8.8},
+		// CVE-2015-1098 (Apple iWork denial of service)
+		{"CVSS:3.1/AV:L/AC:L/PR:N/UI:R/S:U/C:H/I:H/A:H", 7.8, 7.8, 7.8},
+		// CVE-2014-0160 (OpenSSL Heartbleed)
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N", 7.5, 7.5, 7.5},
+		// CVE-2014-6271 (GNU Bourne-Again Shell Shellshock)
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8, 9.8, 9.8},
+		// CVE-2008-1447 (DNS Kaminsky bug)
+		{"CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:C/C:N/I:H/A:N", 6.8, 6.8, 6.8},
+		// CVE-2014-2005 (Sophos login screen bypass)
+		{"CVSS:3.1/AV:P/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 6.8, 6.8, 6.8},
+		// CVE-2010-0467 (Joomla directory traversal)
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:L/I:N/A:N", 5.8, 5.8, 5.8},
+		// CVE-2012-1342 (Cisco access control bypass)
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:N/I:L/A:N", 5.8, 5.8, 5.8},
+		// CVE-2013-6014 (Juniper proxy ARP denial of service)
+		{"CVSS:3.1/AV:A/AC:L/PR:N/UI:N/S:C/C:H/I:N/A:H", 9.3, 9.3, 9.3},
+		// CVE-2014-9253 (DokuWiki reflected XSS)
+		{"CVSS:3.1/AV:N/AC:L/PR:L/UI:R/S:C/C:L/I:L/A:N", 5.4, 5.4, 5.4},
+		// CVE-2011-1265 (Microsoft Windows Bluetooth remote code execution)
+		{"CVSS:3.1/AV:A/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 8.8, 8.8, 8.8},
+		// CVE-2014-2019 (Apple iOS security control bypass)
+		{"CVSS:3.1/AV:P/AC:L/PR:N/UI:N/S:U/C:N/I:H/A:N", 4.6, 4.6, 4.6},
+		// CVE-2015-0970 (SearchBlox cross-site request forgery)
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:U/C:H/I:H/A:H", 8.8, 8.8, 8.8},
+		// CVE-2014-0224 (OpenSSL CCS injection)
+		{"CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:H/A:N", 7.4, 7.4, 7.4},
+		// CVE-2016-0128 / CVE-2016-2118 (Badlock)
+		{"CVSS:3.1/AV:N/AC:H/PR:N/UI:R/S:U/C:H/I:H/A:N", 6.8, 6.8, 6.8},
+		// CVE-2016-1645 (Google Chrome PDFium JPEG 2000 remote code execution)
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:U/C:H/I:H/A:H", 8.8, 8.8, 8.8},
+		// CVE-2018-3652 (Intel DCI execution)
+		{"CVSS:3.1/AV:P/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", 7.6, 7.6, 7.7},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", 0.0, 0.0, 0.0},
+		// Temporal metrics
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/E:U/RL:O/RC:U", 9.8, 7.8, 7.8},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N/E:F/RL:O/RC:C", 7.5, 7.0, 7.0},
+		// Environmental metrics
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/CR:L/IR:L/AR:L", 9.8, 9.8, 8.0},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/MAV:L/MPR:H", 9.8, 9.8, 6.7},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N/MC:N/MI:N", 6.1, 6.1, 0.0},
+		{"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:U/C:L/I:L/A:N/MS:C", 5.4, 5.4, 6.4},
+		// CVSS v3.0 rounds up without correcting floating point errors
+		{"CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8, 9.8, 9.8},
+	} {
+		v, err := Parse(tc.vector)
+		require.Nil(t, err, tc.vector)
+		base, err := v.BaseScore()
+		assert.Nil(t, err)
+		assert.Equal(t, tc.base, base, "base %s", tc.vector)
+		temporal, err := v.TemporalScore()
+		assert.Nil(t, err)
+		assert.Equal(t, tc.temporal, temporal, "temporal %s", tc.vector)
+		environmental, err := v.EnvironmentalScore()
+		assert.Nil(t, err)
+		assert.Equal(t, tc.environmental, environmental, "environmental %s", tc.vector)
+	}
+}
+
+func Test_Vector_roundUp(t *testing.T) {
+	v31, _ := New(Version31)
+	assert.Equal(t, 4.0, v31.roundUp(4.000002))
+	assert.Equal(t, 4.1, v31.roundUp(4.02))
+	assert.Equal(t, 4.0, v31.roundUp(4.0))
+	v30, _ := New(Version30)
+	assert.Equal(t, 4.1, v30.roundUp(4.02))
+}
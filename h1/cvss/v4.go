@@ -0,0 +1,374 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cvss
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// v4Metrics are the metrics of CVSS v4.0
+//
+// Specification: https://www.first.org/cvss/v4.0/specification-document
+var v4Metrics = []metricDefinition{
+	// Base
+	{"AV", []string{"N", "A", "L", "P"}, true},
+	{"AC", []string{"L", "H"}, true},
+	{"AT", []string{"N", "P"}, true},
+	{"PR", []string{"N", "L", "H"}, true},
+	{"UI", []string{"N", "P", "A"}, true},
+	{"VC", []string{"H", "L", "N"}, true},
+	{"VI", []string{"H", "L", "N"}, true},
+	{"VA", []string{"H", "L", "N"}, true},
+	{"SC", []string{"H", "L", "N"}, true},
+	{"SI", []string{"H", "L", "N"}, true},
+	{"SA", []string{"H", "L", "N"}, true},
+	// Threat
+	{"E", []string{"X", "A", "P", "U"}, false},
+	// Environmental
+	{"CR", []string{"X", "H", "M", "L"}, false},
+	{"IR", []string{"X", "H", "M", "L"}, false},
+	{"AR", []string{"X", "H", "M", "L"}, false},
+	{"MAV", []string{"X", "N", "A", "L", "P"}, false},
+	{"MAC", []string{"X", "L", "H"}, false},
+	{"MAT", []string{"X", "N", "P"}, false},
+	{"MPR", []string{"X", "N", "L", "H"}, false},
+	{"MUI", []string{"X", "N", "P", "A"}, false},
+	{"MVC", []string{"X", "H", "L", "N"}, false},
+	{"MVI", []string{"X", "H", "L", "N"}, false},
+	{"MVA", []string{"X", "H", "L", "N"}, false},
+	{"MSC", []string{"X", "H", "L", "N"}, false},
+	{"MSI", []string{"X", "S", "H", "L", "N"}, false},
+	{"MSA", []string{"X", "S", "H", "L", "N"}, false},
+	// Supplemental
+	{"S", []string{"X", "N", "P"}, false},
+	{"AU", []string{"X", "N", "Y"}, false},
+	{"R", []string{"X", "A", "U", "I"}, false},
+	{"V", []string{"X", "D", "C"}, false},
+	{"RE", []string{"X", "L", "M", "H"}, false},
+	{"U", []string{"X", "Clear", "Green", "Amber", "Red"}, false},
+}
+
+// v4Lookup are the scores of the highest severity vector of every CVSS v4.0 MacroVector, keyed by the levels
+// of the six equivalence sets EQ1 to EQ6
+var v4Lookup = map[string]float64{
+	"000000": 10, "000001": 9.9, "000010": 9.8, "000011": 9.5, "000020": 9.5, "000021": 9.2,
+	"000100": 10, "000101": 9.6, "000110": 9.3, "000111": 8.7, "000120": 9.1, "000121": 8.1,
+	"000200": 9.3, "000201": 9, "000210": 8.9, "000211": 8, "000220": 8.1, "000221": 6.8,
+	"001000": 9.8, "001001": 9.5, "001010": 9.5, "001011": 9.2, "001020": 9, "001021": 8.4,
+	"001100": 9.3, "001101": 9.2, "001110": 8.9, "001111": 8.1, "001120": 8.1, "001121": 6.5,
+	"001200": 8.8, "001201": 8, "001210": 7.8, "001211": 7, "001220": 6.9, "001221": 4.8,
+	"002001": 9.2, "002011": 8.2, "002021": 7.2, "002101": 7.9, "002111": 6.9, "002121": 5,
+	"002201": 6.9, "002211": 5.5, "002221": 2.7, "010000": 9.9, "010001": 9.7, "010010": 9.5,
+	"010011": 9.2, "010020": 9.2, "010021": 8.5, "010100": 9.5, "010101": 9.1, "010110": 9,
+	"010111": 8.3, "010120": 8.4, "010121": 7.1, "010200": 9.2, "010201": 8.1, "010210": 8.2,
+	"010211": 7.1, "010220": 7.2, "010221": 5.3, "011000": 9.5, "011001": 9.3, "011010": 9.2,
+	"011011": 8.5, "011020": 8.5, "011021": 7.3, "011100": 9.2, "011101": 8.2, "011110": 8,
+	"011111": 7.2, "011120": 7, "011121": 5.9, "011200": 8.4, "011201": 7, "011210": 7.1,
+	"011211": 5.2, "011220": 5, "011221": 3, "012001": 8.6, "012011": 7.5, "012021": 5.2,
+	"012101": 7.1, "012111": 5.2, "012121": 2.9, "012201": 6.3, "012211": 2.9, "012221": 1.7,
+	"100000": 9.8, "100001": 9.5, "100010": 9.4, "100011": 8.7, "100020": 9.1, "100021": 8.1,
+	"100100": 9.4, "100101": 8.9, "100110": 8.6, "100111": 7.4, "100120": 7.7, "100121": 6.4,
+	"100200": 8.7, "100201": 7.5, "100210": 7.4, "100211": 6.3, "100220": 6.3, "100221": 4.9,
+	"101000": 9.4, "101001": 8.9, "101010": 8.8, "101011": 7.7, "101020": 7.6, "101021": 6.7,
+	"101100": 8.6, "101101": 7.6, "101110": 7.4, "101111": 5.8, "101120": 5.9, "101121": 5,
+	"101200": 7.2, "101201": 5.7, "101210": 5.7, "101211": 5.2, "101220": 5.2, "101221": 2.5,
+	"102001": 8.3, "102011": 7, "102021": 5.4, "102101": 6.5, "102111": 5.8, "102121": 2.6,
+	"102201": 5.3, "102211": 2.1, "102221": 1.3, "110000": 9.5, "110001": 9, "110010": 8.8,
+	"110011": 7.6, "110020": 7.6, "110021": 7, "110100": 9, "110101": 7.7, "110110": 7.5,
+	"110111": 6.2, "110120": 6.1, "110121": 5.3, "110200": 7.7, "110201": 6.6, "110210": 6.8,
+	"110211": 5.9, "110220": 5.2, "110221": 3, "111000": 8.9, "111001": 7.8, "111010": 7.6,
+	"111011": 6.7, "111020": 6.2, "111021": 5.8, "111100": 7.4, "111101": 5.9, "111110": 5.7,
+	"111111": 5.7, "111120": 4.7, "111121": 2.3, "111200": 6.1, "111201": 5.2, "111210": 5.7,
+	"111211": 2.9, "111220": 2.4, "111221": 1.6, "112001": 7.1, "112011": 5.9, "112021": 3,
+	"112101": 5.8, "112111": 2.6, "112121": 1.5, "112201": 2.3, "112211": 1.3, "112221": 0.6,
+	"200000": 9.3, "200001": 8.7, "200010": 8.6, "200011": 7.2, "200020": 7.5, "200021": 5.8,
+	"200100": 8.6, "200101": 7.4, "200110": 7.4, "200111": 6.1, "200120": 5.6, "200121": 3.4,
+	"200200": 7, "200201": 5.4, "200210": 5.2, "200211": 4, "200220": 4, "200221": 2.2,
+	"201000": 8.5, "201001": 7.5, "201010": 7.4, "201011": 5.5, "201020": 6.2, "201021": 5.1,
+	"201100": 7.2, "201101": 5.7, "201110": 5.5, "201111": 4.1, "201120": 4.6, "201121": 1.9,
+	"201200": 5.3, "201201": 3.6, "201210": 3.4, "201211": 1.9, "201220": 1.9, "201221": 0.8,
+	"202001": 6.4, "202011": 5.1, "202021": 2, "202101": 4.7, "202111": 2.1, "202121": 1.1,
+	"202201": 2.4, "202211": 0.9, "202221": 0.4, "210000": 8.8, "210001": 7.5, "210010": 7.3,
+	"210011": 5.3, "210020": 6, "210021": 5, "210100": 7.3, "210101": 5.5, "210110": 5.9,
+	"210111": 4, "210120": 4.1, "210121": 2, "210200": 5.4, "210201": 4.3, "210210": 4.5,
+	"210211": 2.2, "210220": 2, "210221": 1.1, "211000": 7.5, "211001": 5.5, "211010": 5.8,
+	"211011": 4.5, "211020": 4, "211021": 2.1, "211100": 6.1, "211101": 5.1, "211110": 4.8,
+	"211111": 1.8, "211120": 2, "211121": 0.9, "211200": 4.6, "211201": 1.8, "211210": 1.7,
+	"211211": 0.7, "211220": 0.8, "211221": 0.2, "212001": 5.3, "212011": 2.4, "212021": 1.4,
+	"212101": 2.4, "212111": 1.2, "212121": 0.5, "212201": 1, "212211": 0.3, "212221": 0.1,
+}
+
+// v4Levels are the severity levels of the metrics used to measure the distance of a vector from the highest
+// severity vectors of its MacroVector. Lower levels are more severe.
+var v4Levels = map[string]map[string]float64{
+	"AV": {"N": 0, "A": 0.1, "L": 0.2, "P": 0.3},
+	"PR": {"N": 0, "L": 0.1, "H": 0.2},
+	"UI": {"N": 0, "P": 0.1, "A": 0.2},
+	"AC": {"L": 0, "H": 0.1},
+	"AT": {"N": 0, "P": 0.1},
+	"VC": {"H": 0, "L": 0.1, "N": 0.2},
+	"VI": {"H": 0, "L": 0.1, "N": 0.2},
+	"VA": {"H": 0, "L": 0.1, "N": 0.2},
+	"SC": {"H": 0.1, "L": 0.2, "N": 0.3},
+	"SI": {"S": 0, "H": 0.1, "L": 0.2, "N": 0.3},
+	"SA": {"S": 0, "H": 0.1, "L": 0.2, "N": 0.3},
+	"CR": {"H": 0, "M": 0.1, "L": 0.2},
+	"IR": {"H": 0, "M": 0.1, "L": 0.2},
+	"AR": {"H": 0, "M": 0.1, "L": 0.2},
+}
+
+// v4MaxComposed are the highest severity vectors of every level of the equivalence sets. EQ3 and EQ6 are
+// combined, keyed by the level of EQ3 and then of EQ6.
+var v4MaxComposed = struct {
+	eq1, eq2, eq4, eq5 map[int][]string
+	eq3eq6             map[int]map[int][]string
+}{
+	eq1: map[int][]string{
+		0: {"AV:N/PR:N/UI:N"},
+		1: {"AV:A/PR:N/UI:N", "AV:N/PR:L/UI:N", "AV:N/PR:N/UI:P"},
+		2: {"AV:P/PR:N/UI:N", "AV:A/PR:L/UI:P"},
+	},
+	eq2: map[int][]string{
+		0: {"AC:L/AT:N"},
+		1: {"AC:H/AT:N", "AC:L/AT:P"},
+	},
+	eq3eq6: map[int]map[int][]string{
+		0: {
+			0: {"VC:H/VI:H/VA:H/CR:H/IR:H/AR:H"},
+			1: {"VC:H/VI:H/VA:L/CR:M/IR:M/AR:H", "VC:H/VI:H/VA:H/CR:M/IR:M/AR:M"},
+		},
+		1: {
+			0: {"VC:L/VI:H/VA:H/CR:H/IR:H/AR:H", "VC:H/VI:L/VA:H/CR:H/IR:H/AR:H"},
+			1: {"VC:L/VI:H/VA:H/CR:H/IR:M/AR:H", "VC:L/VI:H/VA:L/CR:H/IR:M/AR:H", "VC:H/VI:L/VA:H/CR:M/IR:H/AR:H", "VC:H/VI:L/VA:L/CR:M/IR:H/AR:H", "VC:L/VI:L/VA:H/CR:H/IR:H/AR:M"},
+		},
+		2: {
+			1: {"VC:L/VI:L/VA:L/CR:H/IR:H/AR:H"},
+		},
+	},
+	eq4: map[int][]string{
+		0: {"SC:H/SI:S/SA:S"},
+		1: {"SC:H/SI:H/SA:H"},
+		2: {"SC:L/SI:L/SA:L"},
+	},
+	eq5: map[int][]string{
+		0: {"E:A"},
+		1: {"E:P"},
+		2: {"E:U"},
+	},
+}
+
+// v4MaxSeverity are the depths of every level of the equivalence sets, in steps of 0.1
+var v4MaxSeverity = struct {
+	eq1, eq2, eq4 map[int]float64
+	eq3eq6        map[int]map[int]float64
+}{
+	eq1:    map[int]float64{0: 1, 1: 4, 2: 5},
+	eq2:    map[int]float64{0: 1, 1: 2},
+	eq3eq6: map[int]map[int]float64{0: {0: 7, 1: 6}, 1: {0: 8, 1: 8}, 2: {1: 10}},
+	eq4:    map[int]float64{0: 6, 1: 5, 2: 4},
+}
+
+// v4Effective returns the value of a metric as it is scored. Threat and environmental metrics which are not
+// defined or not included assume their worst case, and modified base metrics replace their base metric.
+func (v *Vector) v4Effective(key string, threat, environmental bool) string {
+	switch key {
+	case "E":
+		if value := v.Metric(key); threat && value != NotDefined {
+			return value
+		}
+		return "A"
+	case "CR", "IR", "AR":
+		if value := v.Metric(key); environmental && value != NotDefined {
+			return value
+		}
+		return "H"
+	}
+	if environmental {
+		return v.modified(key)
+	}
+	return v.Metric(key)
+}
+
+// v4MacroVector returns the levels of the six equivalence sets of the effective metrics
+func v4MacroVector(m func(key string) string) [6]int {
+	var eq [6]int
+	switch {
+	case m("AV") == "N" && m("PR") == "N" && m("UI") == "N":
+		eq[0] = 0
+	case (m("AV") == "N" || m("PR") == "N" || m("UI") == "N") && m("AV") != "P":
+		eq[0] = 1
+	default:
+		eq[0] = 2
+	}
+	if m("AC") != "L" || m("AT") != "N" {
+		eq[1] = 1
+	}
+	switch {
+	case m("VC") == "H" && m("VI") == "H":
+		eq[2] = 0
+	case m("VC") == "H" || m("VI") == "H" || m("VA") == "H":
+		eq[2] = 1
+	default:
+		eq[2] = 2
+	}
+	switch {
+	case m("SI") == "S" || m("SA") == "S":
+		eq[3] = 0
+	case m("SC") == "H" || m("SI") == "H" || m("SA") == "H":
+		eq[3] = 1
+	default:
+		eq[3] = 2
+	}
+	eq[4] = map[string]int{"A": 0, "P": 1, "U": 2}[m("E")]
+	if !(m("CR") == "H" && m("VC") == "H") && !(m("IR") == "H" && m("VI") == "H") && !(m("AR") == "H" && m("VA") == "H") {
+		eq[5] = 1
+	}
+	return eq
+}
+
+// v4Key returns the lookup key of a MacroVector
+func v4Key(eq [6]int) string {
+	var b strings.Builder
+	for _, level := range eq {
+		b.WriteString(strconv.Itoa(level))
+	}
+	return b.String()
+}
+
+// v4Score computes the score of the vector as described in section 8.2 of the specification. The score of
+// its MacroVector is lowered by the mean proportional distance of the vector from the highest severity
+// vectors of the MacroVector, relative to the scores of the next lower MacroVectors.
+func (v *Vector) v4Score(threat, environmental bool) float64 {
+	m := func(key string) string { return v.v4Effective(key, threat, environmental) }
+
+	// Vectors without any impact score zero
+	impact := false
+	for _, key := range []string{"VC", "VI", "VA", "SC", "SI", "SA"} {
+		if m(key) != "N" {
+			impact = true
+		}
+	}
+	if !impact {
+		return 0
+	}
+
+	eq := v4MacroVector(m)
+	value := v4Lookup[v4Key(eq)]
+
+	// The scores of the next lower MacroVector of every equivalence set, NaN if there is none
+	lower := func(deltas ...int) float64 {
+		next := eq
+		for i := 0; i < len(deltas); i += 2 {
+			next[deltas[i]] += deltas[i+1]
+		}
+		if score, ok := v4Lookup[v4Key(next)]; ok {
+			return score
+		}
+		return math.NaN()
+	}
+	lowerEQ1 := lower(0, 1)
+	lowerEQ2 := lower(1, 1)
+	var lowerEQ3EQ6 float64
+	switch {
+	case eq[2] == 0 && eq[5] == 0:
+		// Two paths lead down, take the higher one
+		lowerEQ3EQ6 = lower(2, 1)
+		if left := lower(5, 1); left > lowerEQ3EQ6 {
+			lowerEQ3EQ6 = left
+		}
+	case eq[2] == 1 && eq[5] == 0:
+		lowerEQ3EQ6 = lower(5, 1)
+	case eq[2] == 2:
+		lowerEQ3EQ6 = lower(2, 1, 5, 1)
+	default:
+		lowerEQ3EQ6 = lower(2, 1)
+	}
+	lowerEQ4 := lower(3, 1)
+	lowerEQ5 := lower(4, 1)
+
+	// Find the first highest severity vector of the MacroVector which is at least as severe as the vector
+	var maxVectors []string
+	for _, eq1 := range v4MaxComposed.eq1[eq[0]] {
+		for _, eq2 := range v4MaxComposed.eq2[eq[1]] {
+			for _, eq3eq6 := range v4MaxComposed.eq3eq6[eq[2]][eq[5]] {
+				for _, eq4 := range v4MaxComposed.eq4[eq[3]] {
+					for _, eq5 := range v4MaxComposed.eq5[eq[4]] {
+						maxVectors = append(maxVectors, strings.Join([]string{eq1, eq2, eq3eq6, eq4, eq5}, "/"))
+					}
+				}
+			}
+		}
+	}
+	distances := map[string]float64{}
+	for _, maxVector := range maxVectors {
+		found := true
+		for _, part := range strings.Split(maxVector, "/") {
+			kv := strings.SplitN(part, ":", 2)
+			if kv[0] == "E" {
+				continue
+			}
+			distances[kv[0]] = v4Levels[kv[0]][m(kv[0])] - v4Levels[kv[0]][kv[1]]
+			if distances[kv[0]] < 0 {
+				found = false
+			}
+		}
+		if found {
+			break
+		}
+	}
+
+	// Every available distance to a lower MacroVector is scaled by the proportion of the depth of the
+	// MacroVector which the vector is away from its highest severity vectors
+	const step = 0.1
+	proportional := func(available, distance, depth float64) float64 {
+		return available * (distance / (depth * step))
+	}
+	var total float64
+	existing := 0
+	if available := value - lowerEQ1; !math.IsNaN(available) {
+		existing++
+		total += proportional(available, distances["AV"]+distances["PR"]+distances["UI"], v4MaxSeverity.eq1[eq[0]])
+	}
+	if available := value - lowerEQ2; !math.IsNaN(available) {
+		existing++
+		total += proportional(available, distances["AC"]+distances["AT"], v4MaxSeverity.eq2[eq[1]])
+	}
+	if available := value - lowerEQ3EQ6; !math.IsNaN(available) {
+		existing++
+		total += proportional(available, distances["VC"]+distances["VI"]+distances["VA"]+distances["CR"]+distances["IR"]+distances["AR"],
+			v4MaxSeverity.eq3eq6[eq[2]][eq[5]])
+	}
+	if available := value - lowerEQ4; !math.IsNaN(available) {
+		existing++
+		total += proportional(available, distances["SC"]+distances["SI"]+distances["SA"], v4MaxSeverity.eq4[eq[3]])
+	}
+	if available := value - lowerEQ5; !math.IsNaN(available) {
+		// The threat metric has no depth, so it only counts towards the mean
+		existing++
+	}
+	if existing > 0 {
+		value -= total / float64(existing)
+	}
+	value = math.Min(math.Max(value, 0), 10)
+	return math.Floor(value*10+0.5) / 10
+}
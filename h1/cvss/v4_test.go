@@ -0,0 +1,90 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cvss
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"testing"
+)
+
+func Test_Vector_v4Scores(t *testing.T) {
+	// Examples of the CVSS v4.0 specification (https://www.first.org/cvss/v4.0/examples)
+	for _, tc := range []struct {
+		vector string
+		base   float64
+	}{
+		// CVE-2021-44228 (Log4Shell)
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H", 10.0},
+		// CVE-2022-41741 (nginx mp4 module memory corruption)
+		{"CVSS:4.0/AV:L/AC:L/AT:N/PR:L/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", 8.5},
+		// CVE-2020-3549 (Cisco FMC sftunnel)
+		{"CVSS:4.0/AV:N/AC:H/AT:P/PR:N/UI:P/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", 7.7},
+		// CVE-2014-0160 (OpenSSL Heartbleed)
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:N/VA:N/SC:N/SI:N/SA:N", 8.7},
+		// CVE-2022-22186 (Juniper EX4650 unauthorized access)
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:N/VI:L/VA:N/SC:N/SI:N/SA:N", 6.9},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", 9.3},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:L/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", 8.7},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:H/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", 8.6},
+		{"CVSS:4.0/AV:N/AC:H/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", 9.2},
+		{"CVSS:4.0/AV:N/AC:L/AT:P/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", 9.2},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:N/VI:N/VA:H/SC:N/SI:N/SA:N", 8.7},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:L/VI:N/VA:N/SC:N/SI:N/SA:N", 6.9},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:L/UI:N/VC:L/VI:N/VA:N/SC:N/SI:N/SA:N", 5.3},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:A/VC:N/VI:N/VA:N/SC:L/SI:L/SA:N", 5.1},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:P/VC:N/VI:N/VA:N/SC:L/SI:L/SA:N", 5.3},
+		// Vectors without any impact always score 0
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:N/VI:N/VA:N/SC:N/SI:N/SA:N", 0.0},
+	} {
+		v, err := Parse(tc.vector)
+		require.Nil(t, err, tc.vector)
+		base, err := v.BaseScore()
+		assert.Nil(t, err)
+		assert.Equal(t, tc.base, base, "base %s", tc.vector)
+	}
+
+	// Verify that the threat and environmental scores only differ from the base score when their metrics are set
+	score := func(vector string, f func(*Vector) (float64, error)) float64 {
+		v, err := Parse(vector)
+		require.Nil(t, err, vector)
+		s, err := f(v)
+		require.Nil(t, err, vector)
+		return s
+	}
+	base := "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N"
+	assert.Equal(t, 9.3, score(base, (*Vector).TemporalScore))
+	assert.Equal(t, 9.3, score(base, (*Vector).EnvironmentalScore))
+	assert.Equal(t, 9.3, score(base+"/E:A", (*Vector).TemporalScore))
+	assert.True(t, score(base+"/E:P", (*Vector).TemporalScore) < 9.3)
+	assert.True(t, score(base+"/E:U", (*Vector).TemporalScore) < score(base+"/E:P", (*Vector).TemporalScore))
+	assert.Equal(t, 9.3, score(base+"/E:U", (*Vector).BaseScore))
+	assert.True(t, score(base+"/CR:L/IR:L/AR:L", (*Vector).EnvironmentalScore) < 9.3)
+	assert.Equal(t, 9.3, score(base+"/CR:L/IR:L/AR:L", (*Vector).TemporalScore))
+
+	// Verify that modified base metrics replace the base metrics
+	assert.Equal(t,
+		score("CVSS:4.0/AV:L/AC:L/AT:N/PR:L/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", (*Vector).BaseScore),
+		score(base+"/MAV:L/MPR:L", (*Vector).EnvironmentalScore))
+	assert.Equal(t, 10.0, score(base+"/MSI:S", (*Vector).EnvironmentalScore))
+	assert.Equal(t, 0.0, score(base+"/MVC:N/MVI:N/MVA:N", (*Vector).EnvironmentalScore))
+}
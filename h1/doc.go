@@ -37,6 +37,14 @@ access different parts of the HackerOne API. For example:
 
 	fmt.Println("Report Title:", *report.Title)
 
+Cancellation
+
+Every service method has a WithContext variant which takes a context.Context as its first argument. Requests are aborted as soon as the context is done, including between pages of the ListAll helpers.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	report, _, err := client.Report.GetWithContext(ctx, "123456")
+
 Authentication
 
 The h1 library does not directly handle authentication. Instead, when creating a new client, you can pass a http.Client that handles authentication for you. It does provide a APIAuthTransport structure when using API Token authentication.
@@ -61,5 +69,16 @@ All requests for listing resources such as `Report` support pagination. Paginati
 		}
 		listOpts.Page = resp.Links.NextPageNumber()
 	}
+
+Every paginated list also has an Iter variant which returns an Iterator fetching pages lazily as items are consumed.
+	it := client.Report.ListIter(filter, &h1.IteratorOptions{Prefetch: 2})
+	defer it.Close()
+	for it.Next() {
+		report := it.Value()
+		fmt.Println("Report Title:", *report.Title)
+	}
+	if err := it.Err(); err != nil {
+		panic(err)
+	}
 */
 package h1
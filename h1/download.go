@@ -0,0 +1,208 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrAttachmentSize is returned when a downloaded attachment does not have the size stated by its FileSize.
+var ErrAttachmentSize = errors.New("h1: attachment size mismatch")
+
+// errURLExpired is returned by a download attempt which was rejected because its expiring URL is no longer valid
+var errURLExpired = errors.New("h1: attachment URL expired")
+
+// AttachmentDownloader downloads the files of report and activity attachments through their expiring URLs.
+// When an URL has expired, the parent report is fetched again to get a fresh one.
+type AttachmentDownloader struct {
+	// Client used to fetch reports when an expiring URL has to be refreshed
+	Client *Client
+
+	// HTTP client used to fetch the files. It is separate from the API client, as the files are served from
+	// a different host which must not receive the API credentials.
+	HTTPClient *http.Client
+}
+
+// NewAttachmentDownloader returns a new AttachmentDownloader which refreshes URLs through client. If a nil httpClient is
+// provided, http.DefaultClient will be used to fetch the files.
+func NewAttachmentDownloader(client *Client, httpClient *http.Client) *AttachmentDownloader {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &AttachmentDownloader{Client: client, HTTPClient: httpClient}
+}
+
+// Download streams the file of an attachment of the report with the given ID to w and returns the number of bytes written.
+// If the expiring URL of the attachment has expired, it is replaced with a fresh one from the report.
+func (d *AttachmentDownloader) Download(reportID string, attachment *Attachment, w io.Writer) (int64, error) {
+	return d.DownloadWithContext(context.Background(), reportID, attachment, w)
+}
+
+// DownloadWithContext streams the file of an attachment of the report with the given ID to w using the provided context.
+// If the expiring URL of the attachment has expired, it is replaced with a fresh one from the report.
+func (d *AttachmentDownloader) DownloadWithContext(ctx context.Context, reportID string, attachment *Attachment, w io.Writer) (int64, error) {
+	n, err := d.fetch(ctx, attachment, w)
+	if err != errURLExpired {
+		return n, err
+	}
+	if err := d.refresh(ctx, reportID, attachment); err != nil {
+		return 0, err
+	}
+	n, err = d.fetch(ctx, attachment, w)
+	if err == errURLExpired {
+		return 0, fmt.Errorf("h1: URL of attachment %s expired again after refreshing it", stringValue(attachment.ID))
+	}
+	return n, err
+}
+
+// DownloadToDir downloads the file of an attachment of the report with the given ID into dir and returns its path.
+// The file is named after the attachment ID and file name, and is only created once it has been downloaded completely.
+func (d *AttachmentDownloader) DownloadToDir(reportID string, attachment *Attachment, dir string) (string, error) {
+	return d.DownloadToDirWithContext(context.Background(), reportID, attachment, dir)
+}
+
+// DownloadToDirWithContext downloads the file of an attachment of the report with the given ID into dir using the provided context
+// and returns its path. The file is named after the attachment ID and file name, and is only created once it has been downloaded completely.
+func (d *AttachmentDownloader) DownloadToDirWithContext(ctx context.Context, reportID string, attachment *Attachment, dir string) (string, error) {
+	path := filepath.Join(dir, attachmentFileName(attachment))
+	tmp, err := ioutil.TempFile(dir, ".download-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := d.DownloadWithContext(ctx, reportID, attachment, tmp); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// DownloadReport downloads the files of all attachments of the report and of its activities into dir and returns their paths.
+// It stops at the first attachment which fails to download.
+func (d *AttachmentDownloader) DownloadReport(report *Report, dir string) ([]string, error) {
+	return d.DownloadReportWithContext(context.Background(), report, dir)
+}
+
+// DownloadReportWithContext downloads the files of all attachments of the report and of its activities into dir using the provided context
+// and returns their paths. It stops at the first attachment which fails to download.
+func (d *AttachmentDownloader) DownloadReportWithContext(ctx context.Context, report *Report, dir string) ([]string, error) {
+	var paths []string
+	for _, attachment := range reportAttachments(report) {
+		path, err := d.DownloadToDirWithContext(ctx, stringValue(report.ID), attachment, dir)
+		if err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// fetch streams the file of the attachment to w, verifying its size
+func (d *AttachmentDownloader) fetch(ctx context.Context, attachment *Attachment, w io.Writer) (int64, error) {
+	if attachment.ExpiringURL == nil || *attachment.ExpiringURL == "" {
+		return 0, errURLExpired
+	}
+	rel, err := url.Parse(*attachment.ExpiringURL)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", d.Client.BaseURL.ResolveReference(rel).String(), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := d.HTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		// Expired signed URLs are rejected before any content is sent
+		return 0, errURLExpired
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return 0, fmt.Errorf("h1: downloading attachment %s failed with %s", stringValue(attachment.ID), resp.Status)
+	}
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, err
+	}
+	if attachment.FileSize != nil && n != int64(*attachment.FileSize) {
+		return n, fmt.Errorf("%w: attachment %s is %d bytes instead of %d", ErrAttachmentSize, stringValue(attachment.ID), n, *attachment.FileSize)
+	}
+	return n, nil
+}
+
+// refresh fetches the report again and copies the fresh URL of the attachment into it
+func (d *AttachmentDownloader) refresh(ctx context.Context, reportID string, attachment *Attachment) error {
+	report, _, err := d.Client.Report.GetWithContext(ctx, reportID)
+	if err != nil {
+		return err
+	}
+	for _, fresh := range reportAttachments(report) {
+		if stringValue(fresh.ID) == stringValue(attachment.ID) {
+			attachment.ExpiringURL = fresh.ExpiringURL
+			return nil
+		}
+	}
+	return fmt.Errorf("h1: attachment %s not found in report %s", stringValue(attachment.ID), reportID)
+}
+
+// reportAttachments returns the attachments of the report followed by those of its activities
+func reportAttachments(report *Report) []*Attachment {
+	var attachments []*Attachment
+	for idx := range report.Attachments {
+		attachments = append(attachments, &report.Attachments[idx])
+	}
+	for idx := range report.Activities {
+		activity := &report.Activities[idx]
+		for jdx := range activity.Attachments {
+			attachments = append(attachments, &activity.Attachments[jdx])
+		}
+	}
+	return attachments
+}
+
+// attachmentFileName returns a file name for the attachment which is unique within its report and safe to use in a directory
+func attachmentFileName(attachment *Attachment) string {
+	name := filepath.Base(strings.ReplaceAll(stringValue(attachment.FileName), "\\", "/"))
+	if name == "." || name == "/" || name == ".." {
+		name = "attachment"
+	}
+	return stringValue(attachment.ID) + "-" + name
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// attachmentServer serves files which are only accessible with a "fresh" signature, and a report which hands those out
+func attachmentServer(t *testing.T, files map[string]string) (*httptest.Server, *Client, *int) {
+	reportRequests := 0
+	var server *httptest.Server
+	attachment := func(id, name, file string) string {
+		return fmt.Sprintf(`{"id":"%s","type":"attachment","attributes":{"file_name":"%s","file_size":%d,"expiring_url":"%s/files/%s?signature=fresh"}}`,
+			id, name, len(files[file]), server.URL, file)
+	}
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/reports/1337":
+			reportRequests++
+			fmt.Fprintf(w, `{"data":{"id":"1337","type":"report","relationships":{"attachments":{"data":[%s]},"activities":{"data":[{"id":"1","type":"activity-comment","relationships":{"attachments":{"data":[%s]}}}]}}}}`,
+				attachment("1", "report.txt", "report.txt"), attachment("2", "../activity.txt", "activity.txt"))
+		default:
+			if r.URL.Query().Get("signature") != "fresh" {
+				http.Error(w, "Request has expired", http.StatusForbidden)
+				return
+			}
+			content, ok := files[r.URL.Path[len("/files/"):]]
+			if !ok {
+				http.Error(w, "Oh No", http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprint(w, content)
+		}
+	}))
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+	return server, c, &reportRequests
+}
+
+func Test_AttachmentDownloader_Download(t *testing.T) {
+	server, c, reportRequests := attachmentServer(t, map[string]string{"report.txt": "report", "activity.txt": "activity"})
+	defer server.Close()
+	d := NewAttachmentDownloader(c, nil)
+
+	// Verify that a valid URL is downloaded directly
+	attachment := &Attachment{ID: String("1"), FileSize: Int(6), ExpiringURL: String(server.URL + "/files/report.txt?signature=fresh")}
+	var buf bytes.Buffer
+	n, err := d.Download("1337", attachment, &buf)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6), n)
+	assert.Equal(t, "report", buf.String())
+	assert.Equal(t, 0, *reportRequests)
+
+	// Verify that an expired URL of an activity attachment is refreshed from the report
+	attachment = &Attachment{ID: String("2"), FileSize: Int(8), ExpiringURL: String(server.URL + "/files/activity.txt?signature=stale")}
+	buf.Reset()
+	_, err = d.Download("1337", attachment, &buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "activity", buf.String())
+	assert.Equal(t, 1, *reportRequests)
+	assert.Contains(t, *attachment.ExpiringURL, "signature=fresh")
+
+	// Verify that the size is verified
+	attachment = &Attachment{ID: String("1"), FileSize: Int(7), ExpiringURL: String(server.URL + "/files/report.txt?signature=fresh")}
+	_, err = d.Download("1337", attachment, ioutil.Discard)
+	assert.True(t, errors.Is(err, ErrAttachmentSize))
+
+	// Verify that an attachment which is no longer part of the report fails
+	attachment = &Attachment{ID: String("3"), ExpiringURL: String(server.URL + "/files/gone.txt")}
+	_, err = d.Download("1337", attachment, ioutil.Discard)
+	assert.NotNil(t, err)
+
+	// Verify that other failures are returned as is
+	attachment = &Attachment{ID: String("1"), ExpiringURL: String(server.URL + "/files/missing.txt?signature=fresh")}
+	_, err = d.Download("1337", attachment, ioutil.Discard)
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, *reportRequests)
+}
+
+func Test_AttachmentDownloader_DownloadReport(t *testing.T) {
+	server, c, _ := attachmentServer(t, map[string]string{"report.txt": "report", "activity.txt": "activity"})
+	defer server.Close()
+	d := NewAttachmentDownloader(c, nil)
+	dir, err := ioutil.TempDir("", "h1-attachments")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// Verify that report and activity attachments are archived with safe file names
+	var report Report
+	require.Nil(t, json.Unmarshal([]byte(`{"id":"1337","type":"report","relationships":{"attachments":{"data":[{"id":"1","type":"attachment","attributes":{"file_name":"report.txt","expiring_url":"/files/report.txt"}}]},"activities":{"data":[{"id":"1","type":"activity-comment","relationships":{"attachments":{"data":[{"id":"2","type":"attachment","attributes":{"file_name":"../activity.txt","expiring_url":"/files/activity.txt"}}]}}}]}}}`), &report))
+	paths, err := d.DownloadReport(&report, dir)
+	require.Nil(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "1-report.txt"), filepath.Join(dir, "2-activity.txt")}, paths)
+	content, _ := ioutil.ReadFile(paths[1])
+	assert.Equal(t, "activity", string(content))
+
+	// Verify that failed downloads leave nothing behind
+	_, err = d.DownloadToDir("1337", &Attachment{ID: String("1"), FileSize: Int(1), ExpiringURL: String(server.URL + "/files/report.txt?signature=fresh")}, dir)
+	assert.True(t, errors.Is(err, ErrAttachmentSize))
+	entries, _ := ioutil.ReadDir(dir)
+	assert.Len(t, entries, 2)
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors which an ErrorResponse can be matched against with errors.Is.
+var (
+	// ErrNotFound is matched when the API returns a 404 Not Found.
+	ErrNotFound = errors.New("h1: not found")
+
+	// ErrUnauthorized is matched when the API returns a 401 Unauthorized, usually because of invalid credentials.
+	ErrUnauthorized = errors.New("h1: unauthorized")
+
+	// ErrForbidden is matched when the API returns a 403 Forbidden.
+	ErrForbidden = errors.New("h1: forbidden")
+
+	// ErrValidation is matched when the API rejects a parameter of the request. See ValidationError.
+	ErrValidation = errors.New("h1: validation failed")
+
+	// ErrRateLimited is matched when the API returns a 429 Too Many Requests. See RateLimitError.
+	ErrRateLimited = errors.New("h1: rate limited")
+
+	// ErrConflict is matched when the request conflicts with the current state of the resource,
+	// such as an invalid report state transition. See ConflictError.
+	ErrConflict = errors.New("h1: conflict")
+)
+
+// ValidationError is returned by ErrorResponse.Unwrap when the API rejects a parameter of the request.
+type ValidationError struct {
+	// Offending parameter as reported in the error source. It may be empty.
+	Parameter string
+
+	// Human readable explanation of the problem
+	Detail string
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	if e.Parameter == "" {
+		return fmt.Sprintf("%v: %s", ErrValidation, e.Detail)
+	}
+	return fmt.Sprintf("%v: %s: %s", ErrValidation, e.Parameter, e.Detail)
+}
+
+// Is allows ValidationError to match ErrValidation.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// RateLimitError is returned by ErrorResponse.Unwrap when the API returns a 429 Too Many Requests.
+type RateLimitError struct {
+	// Delay requested by the Retry-After header. It is zero when the header is missing.
+	RetryAfter time.Duration
+
+	// Rate limits reported by the response headers
+	Rate Rate
+}
+
+// Error implements the error interface.
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter == 0 {
+		return ErrRateLimited.Error()
+	}
+	return fmt.Sprintf("%v: retry after %v", ErrRateLimited, e.RetryAfter)
+}
+
+// Is allows RateLimitError to match ErrRateLimited.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// ConflictError is returned by ErrorResponse.Unwrap when the request conflicts with the current state of
+// the resource, such as a report being moved to a state it cannot transition to.
+type ConflictError struct {
+	// Human readable explanation of the problem
+	Detail string
+}
+
+// Error implements the error interface.
+func (e *ConflictError) Error() string {
+	if e.Detail == "" {
+		return ErrConflict.Error()
+	}
+	return fmt.Sprintf("%v: %s", ErrConflict, e.Detail)
+}
+
+// Is allows ConflictError to match ErrConflict.
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// Unwrap returns the typed error matching the status code and errors of the response, so that
+// an ErrorResponse can be inspected with errors.Is and errors.As. It returns nil for other statuses.
+func (r *ErrorResponse) Unwrap() error {
+	if r.Response == nil {
+		return nil
+	}
+	var first Error
+	if len(r.Errors) > 0 {
+		first = r.Errors[0]
+	}
+	switch r.Response.StatusCode {
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return &ConflictError{Detail: first.Detail}
+	case http.StatusTooManyRequests:
+		rateErr := &RateLimitError{}
+		rateErr.RetryAfter, _ = parseRetryAfter(r.Response.Header.Get("Retry-After"), time.Now())
+		rateErr.Rate, _ = parseRate(r.Response.Header)
+		return rateErr
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		// The API reports invalid state transitions as a rejected state parameter
+		if first.Source.Parameter == "state" {
+			return &ConflictError{Detail: first.Detail}
+		}
+		return &ValidationError{Parameter: first.Source.Parameter, Detail: first.Detail}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func errorResponseFor(status int, header http.Header, body string) *ErrorResponse {
+	resp := &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+	return CheckResponse(resp).(*ErrorResponse)
+}
+
+func Test_ErrorResponse_Unwrap(t *testing.T) {
+	// Verify that statuses map onto their sentinels
+	for status, sentinel := range map[int]error{
+		401: ErrUnauthorized,
+		403: ErrForbidden,
+		404: ErrNotFound,
+		409: ErrConflict,
+		422: ErrValidation,
+		429: ErrRateLimited,
+	} {
+		err := errorResponseFor(status, nil, `{"errors":[]}`)
+		assert.True(t, errors.Is(err, sentinel), "status %d", status)
+		assert.False(t, errors.Is(err, errors.New("other")), "status %d", status)
+	}
+
+	// Verify that unknown statuses only unwrap to nil
+	err := errorResponseFor(500, nil, "Internal Server Error")
+	assert.Nil(t, err.Unwrap())
+	assert.Nil(t, (&ErrorResponse{}).Unwrap())
+
+	// Verify that validation errors expose the offending parameter
+	err = errorResponseFor(400, nil, `{"errors":[{"status":400,"title":"Invalid Parameter","detail":"The parameter 'id' is invalid.","source":{"parameter":"id"}}]}`)
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, &ValidationError{Parameter: "id", Detail: "The parameter 'id' is invalid."}, validationErr)
+	assert.Equal(t, "h1: validation failed: id: The parameter 'id' is invalid.", validationErr.Error())
+
+	// Verify that a rejected state is reported as a conflict
+	err = errorResponseFor(422, nil, `{"errors":[{"status":422,"title":"Invalid Parameter","detail":"Report cannot transition to resolved.","source":{"parameter":"state"}}]}`)
+	var conflictErr *ConflictError
+	assert.True(t, errors.As(err, &conflictErr))
+	assert.False(t, errors.Is(err, ErrValidation))
+	assert.Equal(t, "Report cannot transition to resolved.", conflictErr.Detail)
+	assert.Equal(t, "h1: conflict: Report cannot transition to resolved.", conflictErr.Error())
+
+	// Verify that rate limit errors carry the requested delay and the reported rate
+	header := http.Header{}
+	header.Set("Retry-After", "30")
+	header.Set("X-RateLimit-Limit", "600")
+	header.Set("X-RateLimit-Remaining", "0")
+	header.Set("X-RateLimit-Reset", "1500000000")
+	err = errorResponseFor(429, header, "")
+	var rateErr *RateLimitError
+	assert.True(t, errors.As(err, &rateErr))
+	assert.Equal(t, 30*time.Second, rateErr.RetryAfter)
+	assert.Equal(t, 600, rateErr.Rate.Limit)
+	assert.Equal(t, 0, rateErr.Rate.Remaining)
+	assert.Equal(t, "h1: rate limited: retry after 30s", rateErr.Error())
+
+	// Verify that wrapping keeps the chain intact
+	wrapped := fmt.Errorf("triaging report: %w", errorResponseFor(404, nil, ""))
+	assert.True(t, errors.Is(wrapped, ErrNotFound))
+	var errResp *ErrorResponse
+	assert.True(t, errors.As(wrapped, &errResp))
+}
+
+func Test_CheckResponse_Body(t *testing.T) {
+	// Verify that the error body is closed and can still be read
+	closed := false
+	resp := &http.Response{
+		StatusCode: 404,
+		Body: closeNotifier{
+			Reader:  strings.NewReader(`{"errors":[]}`),
+			onClose: func() { closed = true },
+		},
+	}
+	err := CheckResponse(resp)
+	assert.NotNil(t, err)
+	assert.True(t, closed)
+	body, readErr := ioutil.ReadAll(resp.Body)
+	assert.Nil(t, readErr)
+	assert.Equal(t, `{"errors":[]}`, string(body))
+
+	// Verify that a response without a body does not panic
+	assert.True(t, errors.Is(CheckResponse(&http.Response{StatusCode: 403}), ErrForbidden))
+}
+
+func Test_Client_Do_TypedErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(422)
+		fmt.Fprint(w, `{"errors":[{"status":422,"title":"Invalid Parameter","detail":"The parameter 'state' is invalid.","source":{"parameter":"state"}}]}`)
+	}))
+	defer server.Close()
+	client := NewClient(nil)
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that callers can branch on a failed state change
+	_, resp, err := client.Report.ChangeState("1337", "Resolved!", ReportStateResolved, nil)
+	assert.True(t, errors.Is(err, ErrConflict))
+	body, readErr := ioutil.ReadAll(resp.Body)
+	assert.Nil(t, readErr)
+	assert.Contains(t, string(body), "The parameter 'state' is invalid.")
+}
+
+type closeNotifier struct {
+	*strings.Reader
+	onClose func()
+}
+
+func (c closeNotifier) Close() error {
+	c.onClose()
+	return nil
+}
@@ -23,16 +23,20 @@ package h1
 // Imports
 import (
 	"bytes"
+	"context"
 
 	"github.com/google/go-querystring/query"
 	"github.com/google/jsonapi"
 
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
+	"time"
 )
 
 const (
@@ -52,9 +56,19 @@ type Client struct {
 	// User agent used when communicating with the H1 API.
 	UserAgent string
 
+	// Retry policy applied by Do to rate limited and server error responses. Requests are never retried when nil.
+	RetryPolicy *RetryPolicy
+
+	// Client side rate limiter applied by Do before every request. It may be shared by several clients.
+	RateLimiter *RateLimiter
+
+	rateMu sync.Mutex // Protects rate
+	rate   Rate       // Rate limits reported by the most recent response
+
 	common service // Reuse a single struct instead of allocating one for each service on the heap.
 
 	// Services used for talking to different parts of the H1 API.
+	Activity   *ActivityService
 	Credential *CredentialService
 	Report     *ReportService
 	Program    *ProgramService
@@ -109,6 +123,7 @@ func NewClient(httpClient *http.Client) *Client {
 
 	c := &Client{client: httpClient, BaseURL: baseURL, UserAgent: userAgent}
 	c.common.client = c
+	c.Activity = (*ActivityService)(&c.common)
 	c.Credential = (*CredentialService)(&c.common)
 	c.Report = (*ReportService)(&c.common)
 	c.Program = (*ProgramService)(&c.common)
@@ -119,6 +134,11 @@ func NewClient(httpClient *http.Client) *Client {
 
 // NewRequest creates an API request. A relative URL can be provided in urlStr
 func (c *Client) NewRequest(method, urlStr string, data interface{}) (*http.Request, error) {
+	return c.NewRequestWithContext(context.Background(), method, urlStr, data)
+}
+
+// NewRequestWithContext creates an API request bound to ctx. A relative URL can be provided in urlStr
+func (c *Client) NewRequestWithContext(ctx context.Context, method, urlStr string, data interface{}) (*http.Request, error) {
 	rel, err := url.Parse(urlStr)
 	if err != nil {
 		return nil, err
@@ -131,7 +151,7 @@ func (c *Client) NewRequest(method, urlStr string, data interface{}) (*http.Requ
 		}
 	}
 
-	req, err := http.NewRequest(method, c.BaseURL.ResolveReference(rel).String(), body)
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL.ResolveReference(rel).String(), body)
 	if err != nil {
 		return nil, err
 	}
@@ -192,6 +212,9 @@ type Response struct {
 
 	// Links relating to the response
 	Links ResponseLinks `json:"links"`
+
+	// Rate limits reported by the API in the response headers
+	Rate Rate `json:"-"`
 }
 
 // ErrorSource represents an ErrorSource from the JSONAPI specification.
@@ -208,6 +231,7 @@ type Error struct {
 }
 
 // ErrorResponse wraps a http.Response and is returned when the API returns an error.
+// It unwraps to one of the typed errors of this package, such as ErrNotFound or *ValidationError.
 type ErrorResponse struct {
 	Response *http.Response // HTTP response that caused this error
 	Errors   []Error        `json:"errors"` // The individual errors that occured
@@ -224,13 +248,19 @@ type responseWrapper struct {
 	Data interface{} `json:"data"`
 }
 
-// CheckResponse determines if the given http.Response was an error and converts it to a h1.ErrorResponse if so
+// CheckResponse determines if the given http.Response was an error and converts it to a h1.ErrorResponse if so.
+// The body of an error response is closed and replaced with an in-memory copy so that it can still be read.
 func CheckResponse(r *http.Response) error {
 	if c := r.StatusCode; 200 <= c && c <= 299 {
 		return nil
 	}
 	errorResponse := &ErrorResponse{Response: r}
+	if r.Body == nil {
+		return errorResponse
+	}
 	data, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(data))
 	if err == nil && data != nil {
 		// Ignore errors here so we always pass out an ErrorResponse
 		json.Unmarshal(data, errorResponse)
@@ -238,28 +268,71 @@ func CheckResponse(r *http.Response) error {
 	return errorResponse
 }
 
-// Do sends an API request and returns the API response.
+// Do sends an API request and returns the API response. The request is aborted if its context is cancelled.
+// Failed requests are retried according to the client's RetryPolicy.
 func (c *Client) Do(req *http.Request, resource interface{}) (*Response, error) {
-	// Actually do the request
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
+	var response *Response
+	for attempt := 1; ; attempt++ {
+		// Wait for our own budget before spending the API's
+		if err := c.RateLimiter.Wait(req.Context(), req.Method); err != nil {
+			return nil, err
+		}
+
+		// Actually do the request
+		resp, err := c.client.Do(req)
+		if err != nil {
+			// If the context was cancelled, its error is more useful than the transport's
+			if ctxErr := req.Context().Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			return nil, err
+		}
 
-	// Make a response object
-	response := &Response{Response: resp}
+		// Make a response object
+		response = &Response{Response: resp}
+		if rate, ok := parseRate(resp.Header); ok {
+			response.Rate = rate
+			c.rateMu.Lock()
+			c.rate = rate
+			c.rateMu.Unlock()
+			c.RateLimiter.observe(rate)
+		}
 
-	// If API returned an error, return the response and err back to user to inspect
-	if err := CheckResponse(resp); err != nil {
-		return response, err
+		// If API returned an error, return the response and err back to user to inspect
+		err = CheckResponse(resp)
+		if err == nil {
+			break
+		}
+		delay, retry := c.RetryPolicy.backoff(req, resp, attempt)
+		if !retry {
+			return response, err
+		}
+		next, rewindErr := rewindRequest(req)
+		if rewindErr != nil {
+			return response, err
+		}
+		resp.Body.Close()
+
+		// Wait before the next attempt, giving up if the context is done first
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return response, req.Context().Err()
+		case <-timer.C:
+		}
+		req = next
 	}
 
+	defer response.Body.Close()
+
 	// Wrap the response object so we can get data as well
 	wrapper := &responseWrapper{
 		Response: response,
 		Data:     resource,
 	}
-	if err := json.NewDecoder(resp.Body).Decode(wrapper); err != nil {
+	// An empty body, e.g. of a 204 No Content response, leaves resource untouched
+	if err := json.NewDecoder(response.Body).Decode(wrapper); err != nil && err != io.EOF {
 		return response, err
 	}
 
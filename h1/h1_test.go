@@ -25,12 +25,16 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"bytes"
+	"context"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sync"
 	"testing"
+	"time"
 )
 
 func Test_ResponseLinks(t *testing.T) {
@@ -214,3 +218,107 @@ func Test_Client_Do(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, ResponseLinks{}, successResponse.Links)
 }
+
+func Test_NewRequestWithContext(t *testing.T) {
+	// Check that the context is attached to the request
+	client := NewClient(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := client.NewRequestWithContext(ctx, "GET", "/relativepath", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, ctx, req.Context())
+}
+
+func Test_Client_Do_Cancelled(t *testing.T) {
+	// Verify that a hung request is aborted once its deadline passes
+	release := make(chan struct{})
+	defer close(release)
+	hungServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer hungServer.Close()
+	client := NewClient(nil)
+	u, err := url.Parse(hungServer.URL + "/")
+	assert.Nil(t, err)
+	client.BaseURL = u
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, _, err = client.Report.GetWithContext(ctx, "123456")
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// Verify that an already cancelled context never reaches the server
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, _, err = client.Report.GetWithContext(ctx, "123456")
+	assert.Equal(t, context.Canceled, err)
+}
+
+func Test_Client_Do_CancelledMidPagination(t *testing.T) {
+	// Serve an endless list of pages and cancel the current context on every page
+	var mu sync.Mutex
+	var cancel context.CancelFunc
+	requests := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		cancel()
+		fmt.Fprintf(w, `{"data":[],"links":{"next":"%s/reports?page%%5Bnumber%%5D=%d"}}`, server.URL, requests+1)
+	}))
+	defer server.Close()
+	client := NewClient(nil)
+	u, err := url.Parse(server.URL + "/")
+	assert.Nil(t, err)
+	client.BaseURL = u
+
+	// Verify that every paginated helper stops after the page during which it was cancelled
+	listAlls := map[string]func(ctx context.Context) (interface{}, error){
+		"Report.ListAll": func(ctx context.Context) (interface{}, error) {
+			data, _, err := client.Report.ListAllWithContext(ctx, ReportListFilter{})
+			return data, err
+		},
+		"Program.ListAllStructuredScopes": func(ctx context.Context) (interface{}, error) {
+			data, _, err := client.Program.ListAllStructuredScopesWithContext(ctx, "1337")
+			return data, err
+		},
+		"Program.ListAllWeaknesses": func(ctx context.Context) (interface{}, error) {
+			data, _, err := client.Program.ListAllWeaknessesWithContext(ctx, "1337")
+			return data, err
+		},
+		"Program.ListAllMembers": func(ctx context.Context) (interface{}, error) {
+			data, _, err := client.Program.ListAllMembersWithContext(ctx, "1337")
+			return data, err
+		},
+		"Program.ListAllGroups": func(ctx context.Context) (interface{}, error) {
+			data, _, err := client.Program.ListAllGroupsWithContext(ctx, "1337")
+			return data, err
+		},
+		"Credential.ListAllCredentialInquiries": func(ctx context.Context) (interface{}, error) {
+			data, _, err := client.Credential.ListAllCredentialInquiriesWithContext(ctx, "1337")
+			return data, err
+		},
+		"Credential.ListAllCredentialInquiryResponses": func(ctx context.Context) (interface{}, error) {
+			data, _, err := client.Credential.ListAllCredentialInquiryResponsesWithContext(ctx, "1337", "1")
+			return data, err
+		},
+	}
+	for name, listAll := range listAlls {
+		ctx, ctxCancel := context.WithCancel(context.Background())
+		mu.Lock()
+		cancel = ctxCancel
+		requests = 0
+		mu.Unlock()
+
+		data, err := listAll(ctx)
+		assert.Equal(t, context.Canceled, err, name)
+		assert.Nil(t, data, name)
+		mu.Lock()
+		assert.Equal(t, 1, requests, name)
+		mu.Unlock()
+		ctxCancel()
+	}
+}
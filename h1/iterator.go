@@ -0,0 +1,231 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"context"
+	"errors"
+)
+
+// errPaginationLoop is returned by an Iterator and ActivityService.ListAllIncremental when the API links a page to
+// itself as the next one
+var errPaginationLoop = errors.New("h1: next page link points to the current page")
+
+// IteratorOptions specifies the optional parameters of the paginated Iter methods.
+type IteratorOptions struct {
+	// Page to start from, page size and sort order. PageSize defaults to 100.
+	ListOptions ListOptions
+
+	// Number of pages fetched ahead in the background while the current page is consumed. Pages are fetched on demand when zero.
+	Prefetch int
+}
+
+// pageFunc fetches a single page of a paginated list
+type pageFunc[T any] func(ctx context.Context, listOpts *ListOptions) ([]T, *Response, error)
+
+// iteratorPage is a page of results passed from the prefetching goroutine
+type iteratorPage[T any] struct {
+	items []T
+	resp  *Response
+	err   error
+}
+
+// Iterator lazily walks through every item of a paginated list, following ResponseLinks.Next as pages are consumed.
+// Callers which stop before the end of the list should call Close.
+//
+//	it := client.Report.ListIter(filter, nil)
+//	defer it.Close()
+//	for it.Next() {
+//		report := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		panic(err)
+//	}
+type Iterator[T any] struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	client   *Client
+	fetch    pageFunc[T]
+	listOpts ListOptions
+	next     string // URL of the next page, empty until the first page was fetched
+	prefetch int
+	pages    chan iteratorPage[T] // Only used when prefetching
+	items    []T                  // Remaining items of the current page
+	item     T
+	resp     *Response
+	err      error
+	done     bool
+}
+
+// newIterator creates an Iterator which fetches the first page using fetch and the following ones with client
+func newIterator[T any](ctx context.Context, client *Client, fetch pageFunc[T], opts *IteratorOptions) *Iterator[T] {
+	it := &Iterator[T]{client: client, fetch: fetch}
+	if opts != nil {
+		it.listOpts = opts.ListOptions
+		it.prefetch = opts.Prefetch
+	}
+	if it.listOpts.PageSize == 0 {
+		it.listOpts.PageSize = defaultPageSize
+	}
+	it.ctx, it.cancel = context.WithCancel(ctx)
+	return it
+}
+
+// Next advances the iterator to the next item, fetching the next page if needed.
+// It returns false once the list is exhausted, an error occurred or the iterator was closed.
+func (it *Iterator[T]) Next() bool {
+	for len(it.items) == 0 {
+		if it.done {
+			return false
+		}
+		page, ok := it.nextPage()
+		if !ok {
+			// The prefetching goroutine stops early only when the context is done
+			it.err = it.ctx.Err()
+			it.finish()
+			return false
+		}
+		if page.resp != nil {
+			it.resp = page.resp
+		}
+		if page.err != nil {
+			it.err = page.err
+			it.finish()
+			return false
+		}
+		it.items = page.items
+	}
+	it.item, it.items = it.items[0], it.items[1:]
+	return true
+}
+
+// Value returns the current item. It is only valid after a call to Next returned true.
+func (it *Iterator[T]) Value() T {
+	return it.item
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Response returns the API response of the most recently fetched page.
+func (it *Iterator[T]) Response() *Response {
+	return it.resp
+}
+
+// Close stops the iteration and any page prefetching in progress.
+func (it *Iterator[T]) Close() {
+	it.items = nil
+	it.finish()
+}
+
+// finish marks the iterator as done and releases its context
+func (it *Iterator[T]) finish() {
+	it.done = true
+	it.cancel()
+}
+
+// nextPage returns the next page of results, or false once there are no more pages
+func (it *Iterator[T]) nextPage() (iteratorPage[T], bool) {
+	if it.prefetch <= 0 {
+		page := it.fetchPage()
+		if page.err != nil || page.resp.Links.Next == "" {
+			it.done = true
+		}
+		return page, true
+	}
+	if it.pages == nil {
+		it.pages = make(chan iteratorPage[T], it.prefetch)
+		go it.prefetchPages()
+	}
+	page, ok := <-it.pages
+	return page, ok
+}
+
+// prefetchPages fetches pages in the background until the list is exhausted or the iterator is closed
+func (it *Iterator[T]) prefetchPages() {
+	defer close(it.pages)
+	for {
+		page := it.fetchPage()
+		select {
+		case it.pages <- page:
+		case <-it.ctx.Done():
+			return
+		}
+		if page.err != nil || page.resp.Links.Next == "" {
+			return
+		}
+	}
+}
+
+// fetchPage fetches the first page described by listOpts, or the page ResponseLinks.Next of the previous one
+// pointed to
+func (it *Iterator[T]) fetchPage() iteratorPage[T] {
+	if err := it.ctx.Err(); err != nil {
+		return iteratorPage[T]{err: err}
+	}
+	var items []T
+	var resp *Response
+	var err error
+	current := it.next
+	if current == "" {
+		items, resp, err = it.fetch(it.ctx, &it.listOpts)
+	} else {
+		items, resp, err = it.fetchNext(current)
+	}
+	if err == nil {
+		it.next = resp.Links.Next
+		if it.next != "" && it.next == current {
+			err = errPaginationLoop
+		}
+	}
+	return iteratorPage[T]{items: items, resp: resp, err: err}
+}
+
+// fetchNext fetches the page at the given link
+func (it *Iterator[T]) fetchNext(link string) ([]T, *Response, error) {
+	req, err := it.client.NewRequestWithContext(it.ctx, "GET", link, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data := new([]T)
+	resp, err := it.client.Do(req, data)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return *data, resp, err
+}
+
+// collect drains an Iterator into a slice
+func collect[T any](it *Iterator[T]) ([]T, *Response, error) {
+	defer it.Close()
+	data := []T{}
+	for it.Next() {
+		data = append(data, it.Value())
+	}
+	if err := it.Err(); err != nil {
+		return nil, it.Response(), err
+	}
+	return data, it.Response(), nil
+}
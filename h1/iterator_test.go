@@ -0,0 +1,249 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// pagedServer serves reports over the given number of pages of two reports each, failing on errorPage if non-zero
+func pagedServer(t *testing.T, pages, errorPage int, requests *int32) (*httptest.Server, *Client) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		page := 1
+		fmt.Sscanf(r.URL.Query().Get("page[number]"), "%d", &page)
+		assert.Equal(t, "100", r.URL.Query().Get("page[size]"))
+		if page == errorPage {
+			http.Error(w, "Oh No", 500)
+			return
+		}
+		var items []string
+		for i := 1; i <= 2; i++ {
+			items = append(items, fmt.Sprintf(`{"id":"%d","type":"report"}`, page*10+i))
+		}
+		next := ""
+		if page < pages {
+			next = fmt.Sprintf("%s/reports?page%%5Bnumber%%5D=%d&page%%5Bsize%%5D=100", server.URL, page+1)
+		}
+		fmt.Fprintf(w, `{"data":[%s],"links":{"next":"%s"}}`, strings.Join(items, ","), next)
+	}))
+	c := NewClient(nil)
+	u, err := url.Parse(server.URL + "/")
+	assert.Nil(t, err)
+	c.BaseURL = u
+	return server, c
+}
+
+func iteratorIDs(it *Iterator[Report]) []string {
+	var ids []string
+	for it.Next() {
+		ids = append(ids, *it.Value().ID)
+	}
+	return ids
+}
+
+func Test_Iterator(t *testing.T) {
+	var requests int32
+	server, c := pagedServer(t, 3, 0, &requests)
+	defer server.Close()
+
+	// Verify that every page is walked through lazily
+	it := c.Report.ListIter(ReportListFilter{}, nil)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+	assert.True(t, it.Next())
+	assert.Equal(t, String("11"), it.Value().ID)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	assert.Equal(t, []string{"12", "21", "22", "31", "32"}, iteratorIDs(it))
+	assert.Nil(t, it.Err())
+	assert.Equal(t, 200, it.Response().StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	// Verify that closing the iterator stops fetching pages
+	atomic.StoreInt32(&requests, 0)
+	it = c.Report.ListIter(ReportListFilter{}, nil)
+	assert.True(t, it.Next())
+	it.Close()
+	assert.False(t, it.Next())
+	assert.Nil(t, it.Err())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// Verify that ListAll returns the last response
+	reports, resp, err := c.Report.ListAll(ReportListFilter{})
+	assert.Nil(t, err)
+	assert.Len(t, reports, 6)
+	assert.Equal(t, "", resp.Links.Next)
+}
+
+func Test_Iterator_NextLinks(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Pages are linked by an opaque cursor rather than a page number, and the page after "loop" is itself
+		next := map[string]string{"": "a", "a": "b", "loop": "loop"}[r.URL.Query().Get("page[after]")]
+		link := ""
+		if next != "" {
+			link = server.URL + "/reports?page%5Bafter%5D=" + next
+		}
+		fmt.Fprintf(w, `{"data":[{"id":%q,"type":"report"}],"links":{"next":%q}}`, r.URL.Query().Get("page[after]"), link)
+	}))
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that the next links are followed as they are
+	it := c.Report.ListIter(ReportListFilter{}, nil)
+	assert.Equal(t, []string{"", "a", "b"}, iteratorIDs(it))
+	assert.Nil(t, it.Err())
+
+	// Verify that a next link pointing to the current page stops the iteration
+	it = newIterator(context.Background(), c, func(ctx context.Context, listOpts *ListOptions) ([]Report, *Response, error) {
+		return c.Report.ListWithContext(ctx, ReportListFilter{}, listOpts)
+	}, nil)
+	it.next = server.URL + "/reports?page%5Bafter%5D=loop"
+	assert.Empty(t, iteratorIDs(it))
+	assert.Equal(t, errPaginationLoop, it.Err())
+}
+
+func Test_Iterator_Error(t *testing.T) {
+	var requests int32
+	server, c := pagedServer(t, 3, 2, &requests)
+	defer server.Close()
+
+	// Verify that an error stops the iteration and is reported
+	it := c.Report.ListIter(ReportListFilter{}, nil)
+	assert.Equal(t, []string{"11", "12"}, iteratorIDs(it))
+	assert.NotNil(t, it.Err())
+	assert.Equal(t, 500, it.Response().StatusCode)
+	assert.False(t, it.Next())
+
+	// Verify that the error is passed on by ListAll
+	reports, resp, err := c.Report.ListAll(ReportListFilter{})
+	assert.NotNil(t, err)
+	assert.Nil(t, reports)
+	assert.Equal(t, 500, resp.StatusCode)
+
+	// Verify that cancelling the context stops the iteration
+	ctx, cancel := context.WithCancel(context.Background())
+	it = c.Report.ListIterWithContext(ctx, ReportListFilter{}, nil)
+	assert.True(t, it.Next())
+	cancel()
+	assert.True(t, it.Next())
+	assert.False(t, it.Next())
+	assert.Equal(t, context.Canceled, it.Err())
+}
+
+func Test_Iterator_Prefetch(t *testing.T) {
+	var requests int32
+	server, c := pagedServer(t, 5, 0, &requests)
+	defer server.Close()
+
+	// Verify that pages are fetched ahead of time
+	it := c.Report.ListIter(ReportListFilter{}, &IteratorOptions{Prefetch: 2})
+	assert.True(t, it.Next())
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt32(&requests) >= 3
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"12", "21", "22", "31", "32", "41", "42", "51", "52"}, iteratorIDs(it))
+	assert.Nil(t, it.Err())
+	assert.Equal(t, int32(5), atomic.LoadInt32(&requests))
+
+	// Verify that errors are passed on when prefetching
+	errorServer, c := pagedServer(t, 5, 3, &requests)
+	defer errorServer.Close()
+	it = c.Report.ListIter(ReportListFilter{}, &IteratorOptions{Prefetch: 2})
+	assert.Equal(t, []string{"11", "12", "21", "22"}, iteratorIDs(it))
+	assert.NotNil(t, it.Err())
+
+	// Verify that closing the iterator stops the prefetching
+	atomic.StoreInt32(&requests, 0)
+	it = c.Report.ListIter(ReportListFilter{}, &IteratorOptions{Prefetch: 1})
+	assert.True(t, it.Next())
+	it.Close()
+	assert.False(t, it.Next())
+	assert.Nil(t, it.Err())
+	time.Sleep(10 * time.Millisecond)
+	assert.True(t, atomic.LoadInt32(&requests) <= 3)
+}
+
+func Test_Iterator_ListMethods(t *testing.T) {
+	var requests int32
+	server, c := pagedServer(t, 2, 0, &requests)
+	defer server.Close()
+
+	// Verify that every paginated list is exposed as an Iterator
+	scopes := c.Program.ListStructuredScopesIter("1337", nil)
+	count := 0
+	for scopes.Next() {
+		count++
+	}
+	assert.Nil(t, scopes.Err())
+	assert.Equal(t, 4, count)
+
+	weaknesses := c.Program.ListWeaknessesIter("1337", nil)
+	count = 0
+	for weaknesses.Next() {
+		count++
+	}
+	assert.Nil(t, weaknesses.Err())
+	assert.Equal(t, 4, count)
+
+	members := c.Program.ListMembersIter("1337", nil)
+	count = 0
+	for members.Next() {
+		count++
+	}
+	assert.Nil(t, members.Err())
+	assert.Equal(t, 4, count)
+
+	groups := c.Program.ListGroupsIter("1337", nil)
+	count = 0
+	for groups.Next() {
+		count++
+	}
+	assert.Nil(t, groups.Err())
+	assert.Equal(t, 4, count)
+
+	inquiries := c.Credential.ListCredentialInquiriesIter("1337", nil)
+	count = 0
+	for inquiries.Next() {
+		count++
+	}
+	assert.Nil(t, inquiries.Err())
+	assert.Equal(t, 4, count)
+
+	responses := c.Credential.ListCredentialInquiryResponsesIter("1337", "1", nil)
+	count = 0
+	for responses.Next() {
+		count++
+	}
+	assert.Nil(t, responses.Err())
+	assert.Equal(t, 4, count)
+}
@@ -23,6 +23,9 @@ package h1
 import (
 	"github.com/stretchr/testify/assert"
 
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -116,3 +119,115 @@ func Test_ProgramService_Get(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, &expectedProgram, actual)
 }
+
+func Test_ProgramService_CreateGroup(t *testing.T) {
+	var requests []string
+	server := resourceServer(t, "tests/resources/group.json", &requests)
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that the group is posted and returned
+	group, _, err := c.Program.CreateGroup("1337", "Triage rotation", []string{GroupPermissionReportManagement})
+	assert.Nil(t, err)
+	assert.Equal(t, `POST /programs/1337/groups {"data":{"type":"group","attributes":{"name":"Triage rotation","permissions":["report_management"]}}}`+"\n", requests[0])
+	assert.NotNil(t, group.ID)
+
+	// Verify that invalid groups are rejected before sending a request
+	_, _, err = c.Program.CreateGroup("1337", "", nil)
+	assert.True(t, errors.Is(err, ErrValidation))
+	_, _, err = c.Program.CreateGroup("1337", "Triage rotation", []string{"admin"})
+	assert.True(t, errors.Is(err, ErrValidation))
+	_, _, err = c.Program.CreateGroup("1337", "Triage rotation", []string{GroupPermissionReportManagement, GroupPermissionReportManagement})
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.Len(t, requests, 1)
+}
+
+func Test_ProgramService_UpdateGroup(t *testing.T) {
+	var requests []string
+	server := resourceServer(t, "tests/resources/group.json", &requests)
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that the group is updated and returned
+	_, _, err := c.Program.UpdateGroup("1337", "2557", "Triage rotation", []string{GroupPermissionReportManagement, GroupPermissionRewardManagement})
+	assert.Nil(t, err)
+	assert.Equal(t, `PUT /programs/1337/groups/2557 {"data":{"type":"group","id":"2557","attributes":{"name":"Triage rotation","permissions":["report_management","reward_management"]}}}`+"\n", requests[0])
+
+	// Verify that invalid groups are rejected before sending a request
+	_, _, err = c.Program.UpdateGroup("1337", "", "Triage rotation", nil)
+	assert.True(t, errors.Is(err, ErrValidation))
+	_, _, err = c.Program.UpdateGroup("1337", "2557", "Triage rotation", []string{"admin"})
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.Len(t, requests, 1)
+}
+
+func Test_ProgramService_GroupMembers(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that members are added and removed
+	_, err := c.Program.AddGroupMember("1337", "2557", "42")
+	assert.Nil(t, err)
+	_, err = c.Program.RemoveGroupMember("1337", "2557", "42")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		`POST /programs/1337/groups/2557/members {"data":{"type":"member","id":"42"}}` + "\n",
+		`DELETE /programs/1337/groups/2557/members/42 `,
+	}, requests)
+
+	// Verify that a member ID is required
+	_, err = c.Program.AddGroupMember("1337", "2557", "")
+	assert.True(t, errors.Is(err, ErrValidation))
+	_, err = c.Program.RemoveGroupMember("1337", "2557", "")
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.Len(t, requests, 2)
+}
+
+func Test_ProgramService_StructuredScopes(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		if r.Method == "DELETE" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"id":"57","type":"structured-scope","attributes":{"asset_type":"URL","asset_identifier":"api.example.com"}}}`)
+	}))
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that a structured scope is created, updated and archived
+	scope, _, err := c.Program.CreateStructuredScope("1337", &StructuredScope{AssetType: "URL", AssetIdentifier: "api.example.com", EligibleForSubmission: true, MaxSeverity: "high"})
+	assert.Nil(t, err)
+	assert.Equal(t, String("57"), scope.ID)
+	scope.Instruction = String("No DoS")
+	_, _, err = c.Program.UpdateStructuredScope("1337", scope)
+	assert.Nil(t, err)
+	_, err = c.Program.ArchiveStructuredScope("1337", "57")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		`POST /programs/1337/structured_scopes {"data":{"type":"structured-scope","attributes":{"asset_identifier":"api.example.com","asset_type":"URL","eligible_for_bounty":false,"eligible_for_submission":true,"max_severity":"high"}}}` + "\n",
+		`PUT /programs/1337/structured_scopes/57 {"data":{"type":"structured-scope","id":"57","attributes":{"asset_identifier":"api.example.com","asset_type":"URL","eligible_for_bounty":false,"eligible_for_submission":false,"instruction":"No DoS"}}}` + "\n",
+		`DELETE /programs/1337/structured_scopes/57 `,
+	}, requests)
+
+	// Verify that invalid structured scopes are rejected before sending a request
+	_, _, err = c.Program.CreateStructuredScope("1337", &StructuredScope{AssetType: "URL"})
+	assert.True(t, errors.Is(err, ErrValidation))
+	_, _, err = c.Program.UpdateStructuredScope("1337", &StructuredScope{AssetType: "URL", AssetIdentifier: "api.example.com"})
+	assert.True(t, errors.Is(err, ErrValidation))
+	_, err = c.Program.ArchiveStructuredScope("1337", "")
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.Len(t, requests, 3)
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Rate represents the request quota reported by the API through the X-RateLimit-* response headers.
+type Rate struct {
+	// Number of requests allowed in the current window
+	Limit int `json:"limit"`
+
+	// Number of requests remaining in the current window
+	Remaining int `json:"remaining"`
+
+	// Time at which the current window resets
+	Reset Timestamp `json:"reset"`
+}
+
+// parseRate extracts the rate limit headers of a response
+func parseRate(header http.Header) (Rate, bool) {
+	var rate Rate
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return rate, false
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return rate, false
+	}
+	rate.Limit = limit
+	rate.Remaining = remaining
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rate.Reset = Timestamp{time.Unix(reset, 0).UTC()}
+	}
+	return rate, true
+}
+
+// Rate returns the rate limits reported by the most recent API response.
+func (c *Client) Rate() Rate {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	return c.rate
+}
+
+// RateLimit configures a token bucket. A bucket with a zero Rate never limits requests.
+type RateLimit struct {
+	// Number of requests allowed per second on average
+	Rate float64
+
+	// Number of requests which can be made at once after a quiet period
+	Burst int
+}
+
+// RateBudget represents the number of requests a RateLimiter currently allows without waiting. Unlimited classes report +Inf.
+type RateBudget struct {
+	Read  float64
+	Write float64
+}
+
+// RateLimiter is a client side token bucket limiter with separate budgets for read (GET, HEAD, OPTIONS) and write requests.
+// Once the API reports that its quota is exhausted, every request waits until the quota resets. The zero value never
+// limits requests itself but still honours an exhausted quota.
+type RateLimiter struct {
+	mu          sync.Mutex
+	read        bucket
+	write       bucket
+	pausedUntil time.Time
+	now         func() time.Time // Overridden in tests, time.Now when nil
+}
+
+// NewRateLimiter returns a RateLimiter with the given limits for read and write requests.
+func NewRateLimiter(read, write RateLimit) *RateLimiter {
+	now := time.Now()
+	return &RateLimiter{
+		read:  bucket{limit: read, tokens: float64(read.Burst), last: now},
+		write: bucket{limit: write, tokens: float64(write.Burst), last: now},
+		now:   time.Now,
+	}
+}
+
+// Budget returns the number of requests of each class which can currently be made without waiting. A nil limiter
+// never limits requests, so both budgets are +Inf.
+func (l *RateLimiter) Budget() RateBudget {
+	if l == nil {
+		return RateBudget{Read: math.Inf(1), Write: math.Inf(1)}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock()
+	l.read.advance(now)
+	l.write.advance(now)
+	return RateBudget{
+		Read:  l.read.budget(),
+		Write: l.write.budget(),
+	}
+}
+
+// Wait blocks until a request with the given method is allowed, or until ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, method string) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	b := &l.write
+	if isReadMethod(method) {
+		b = &l.read
+	}
+	now := l.clock()
+	delay := b.reserve(now)
+	if pause := l.pausedUntil.Sub(now); pause > delay {
+		delay = pause
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		// Give the token back as the request will not be made
+		l.mu.Lock()
+		b.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// clock returns the current time
+func (l *RateLimiter) clock() time.Time {
+	if l.now == nil {
+		return time.Now()
+	}
+	return l.now()
+}
+
+// observe pauses all requests until the reset time once the API reports an exhausted quota
+func (l *RateLimiter) observe(rate Rate) {
+	if l == nil || rate.Remaining > 0 || rate.Reset.IsZero() {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if rate.Reset.After(l.pausedUntil) {
+		l.pausedUntil = rate.Reset.Time
+	}
+}
+
+// isReadMethod reports whether method only reads data
+func isReadMethod(method string) bool {
+	switch method {
+	case "", "GET", "HEAD", "OPTIONS":
+		return true
+	}
+	return false
+}
+
+// bucket is a single token bucket. It is not safe for concurrent use.
+type bucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+// advance refills the bucket for the time elapsed since the last call
+func (b *bucket) advance(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * b.limit.Rate
+		if max := float64(b.limit.Burst); b.tokens > max {
+			b.tokens = max
+		}
+	}
+	b.last = now
+}
+
+// reserve takes a token from the bucket and returns how long to wait before it may be used
+func (b *bucket) reserve(now time.Time) time.Duration {
+	if b.limit.Rate <= 0 {
+		return 0
+	}
+	b.advance(now)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.limit.Rate * float64(time.Second))
+}
+
+// budget returns the number of tokens available right now
+func (b *bucket) budget() float64 {
+	if b.limit.Rate <= 0 {
+		return math.Inf(1)
+	}
+	if b.tokens < 0 {
+		return 0
+	}
+	return b.tokens
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func Test_parseRate(t *testing.T) {
+	// Verify that missing headers are ignored
+	_, ok := parseRate(http.Header{})
+	assert.False(t, ok)
+
+	// Verify that the headers are parsed
+	rate, ok := parseRate(http.Header{
+		"X-Ratelimit-Limit":     []string{"600"},
+		"X-Ratelimit-Remaining": []string{"42"},
+		"X-Ratelimit-Reset":     []string{"1454385906"},
+	})
+	assert.True(t, ok)
+	assert.Equal(t, Rate{
+		Limit:     600,
+		Remaining: 42,
+		Reset:     *NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}, rate)
+}
+
+func Test_RateLimiter(t *testing.T) {
+	now := time.Date(2016, 2, 2, 4, 5, 6, 0, time.UTC)
+	limiter := NewRateLimiter(RateLimit{Rate: 10, Burst: 2}, RateLimit{})
+	limiter.read.last = now
+	limiter.write.last = now
+	limiter.now = func() time.Time { return now }
+
+	// Verify that the burst is available straight away and writes are unlimited
+	assert.Equal(t, RateBudget{Read: 2, Write: math.Inf(1)}, limiter.Budget())
+	assert.Nil(t, limiter.Wait(context.Background(), "GET"))
+	assert.Nil(t, limiter.Wait(context.Background(), "HEAD"))
+	assert.Nil(t, limiter.Wait(context.Background(), "POST"))
+	assert.Equal(t, float64(0), limiter.Budget().Read)
+
+	// Verify that the budget refills over time
+	now = now.Add(150 * time.Millisecond)
+	assert.InDelta(t, 1.5, limiter.Budget().Read, 0.001)
+	now = now.Add(time.Hour)
+	assert.Equal(t, float64(2), limiter.Budget().Read)
+
+	// Verify that an empty bucket makes requests wait until the context is done
+	limiter.read.tokens = 0
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, limiter.Wait(ctx, "GET"))
+	assert.Equal(t, float64(0), limiter.Budget().Read)
+
+	// Verify that an exhausted API quota pauses every request until it resets
+	limiter.observe(Rate{Limit: 600, Remaining: 0, Reset: Timestamp{now.Add(time.Minute)}})
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, limiter.Wait(ctx, "POST"))
+	now = now.Add(time.Minute)
+	assert.Nil(t, limiter.Wait(context.Background(), "POST"))
+
+	// Verify that a nil limiter never waits
+	var nilLimiter *RateLimiter
+	assert.Nil(t, nilLimiter.Wait(context.Background(), "GET"))
+	assert.Equal(t, RateBudget{Read: math.Inf(1), Write: math.Inf(1)}, nilLimiter.Budget())
+
+	// Verify that the zero value never limits requests
+	zeroLimiter := &RateLimiter{}
+	assert.Equal(t, RateBudget{Read: math.Inf(1), Write: math.Inf(1)}, zeroLimiter.Budget())
+	assert.Nil(t, zeroLimiter.Wait(context.Background(), "GET"))
+	assert.Nil(t, zeroLimiter.Wait(context.Background(), "POST"))
+}
+
+func Test_Client_Do_RateLimit(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Limit", "600")
+		w.Header().Set("X-RateLimit-Remaining", "599")
+		w.Header().Set("X-RateLimit-Reset", "1454385906")
+		io.WriteString(w, `{"data":{"id":"1337","type":"program"}}`)
+	}))
+	defer server.Close()
+	c := NewClient(nil)
+	u, err := url.Parse(server.URL + "/")
+	assert.Nil(t, err)
+	c.BaseURL = u
+	c.RateLimiter = NewRateLimiter(RateLimit{Rate: 0.001, Burst: 1}, RateLimit{Rate: 0.001, Burst: 1})
+
+	// Verify that the rate is exposed on the response and the client
+	_, resp, err := c.Program.Get("1337")
+	assert.Nil(t, err)
+	expected := Rate{
+		Limit:     600,
+		Remaining: 599,
+		Reset:     *NewTimestamp("2016-02-02T04:05:06.000Z"),
+	}
+	assert.Equal(t, expected, resp.Rate)
+	assert.Equal(t, expected, c.Rate())
+
+	// Verify that the budget is shared between services
+	assert.InDelta(t, 0, c.RateLimiter.Budget().Read, 0.01)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err = c.Report.GetWithContext(ctx, "1337")
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 1, requests)
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"context"
+	"fmt"
+)
+
+// StructuredScopeOperation represent possible operations of a StructuredScopeChange
+const (
+	StructuredScopeOperationCreate  string = "create"
+	StructuredScopeOperationUpdate  string = "update"
+	StructuredScopeOperationArchive string = "archive"
+)
+
+// StructuredScopeChange is a single operation which brings a program closer to its desired structured scopes
+type StructuredScopeChange struct {
+	// One of the StructuredScopeOperation values
+	Operation string
+
+	// Scope as it currently exists in the program, nil when it is created
+	Current *StructuredScope
+
+	// Scope as it is desired, nil when it is archived. For updates it carries the ID of Current.
+	Desired *StructuredScope
+}
+
+// StructuredScopePlan is the list of changes which reconcile the structured scopes of a program with a desired list.
+// Scopes are matched on their AssetType and AssetIdentifier.
+type StructuredScopePlan struct {
+	ProgramID string
+	Changes   []StructuredScopeChange
+}
+
+// structuredScopeKey identifies the asset of a structured scope
+type structuredScopeKey struct {
+	assetType       string
+	assetIdentifier string
+}
+
+// structuredScopeKeyOf returns the key under which a structured scope is reconciled
+func structuredScopeKeyOf(scope *StructuredScope) structuredScopeKey {
+	return structuredScopeKey{assetType: scope.AssetType, assetIdentifier: scope.AssetIdentifier}
+}
+
+// DiffStructuredScopes plans the changes which turn the current structured scopes of a program into the desired ones.
+// Desired scopes which do not exist yet are created, the ones which differ are updated and current scopes which are not
+// desired are archived. Nil pointer fields of a desired scope, such as Instruction, keep their current value.
+func DiffStructuredScopes(programID string, current, desired []StructuredScope) (*StructuredScopePlan, error) {
+	plan := &StructuredScopePlan{ProgramID: programID}
+
+	existing := make(map[structuredScopeKey]*StructuredScope, len(current))
+	for idx := range current {
+		existing[structuredScopeKeyOf(&current[idx])] = &current[idx]
+	}
+
+	wanted := make(map[structuredScopeKey]bool, len(desired))
+	for idx := range desired {
+		scope := desired[idx]
+		if err := validateStructuredScope(&scope); err != nil {
+			return nil, err
+		}
+		key := structuredScopeKeyOf(&scope)
+		if wanted[key] {
+			return nil, &ValidationError{
+				Parameter: "asset_identifier",
+				Detail:    fmt.Sprintf("%s asset %q is desired more than once", key.assetType, key.assetIdentifier),
+			}
+		}
+		wanted[key] = true
+
+		found, ok := existing[key]
+		if !ok {
+			plan.Changes = append(plan.Changes, StructuredScopeChange{Operation: StructuredScopeOperationCreate, Desired: &scope})
+			continue
+		}
+		merged := mergeStructuredScope(found, &scope)
+		if !structuredScopeEqual(found, merged) {
+			plan.Changes = append(plan.Changes, StructuredScopeChange{Operation: StructuredScopeOperationUpdate, Current: found, Desired: merged})
+		}
+	}
+
+	for idx := range current {
+		if !wanted[structuredScopeKeyOf(&current[idx])] {
+			plan.Changes = append(plan.Changes, StructuredScopeChange{Operation: StructuredScopeOperationArchive, Current: &current[idx]})
+		}
+	}
+	return plan, nil
+}
+
+// mergeStructuredScope returns desired with the ID of current and the current values of its unset fields. Unset fields
+// are omitted from the update request, so keeping the current values makes the plan converge.
+func mergeStructuredScope(current, desired *StructuredScope) *StructuredScope {
+	merged := *desired
+	merged.ID = current.ID
+	merged.Type = current.Type
+	merged.CreatedAt = current.CreatedAt
+	merged.UpdatedAt = current.UpdatedAt
+	for _, field := range []struct {
+		merged  **string
+		current *string
+	}{
+		{&merged.Instruction, current.Instruction},
+		{&merged.ConfidentialityRequirement, current.ConfidentialityRequirement},
+		{&merged.IntegrityRequirement, current.IntegrityRequirement},
+		{&merged.AvailabilityRequirement, current.AvailabilityRequirement},
+		{&merged.Reference, current.Reference},
+	} {
+		if *field.merged == nil {
+			*field.merged = field.current
+		}
+	}
+	if merged.MaxSeverity == "" {
+		merged.MaxSeverity = current.MaxSeverity
+	}
+	return &merged
+}
+
+// structuredScopeEqual reports whether two structured scopes have the same writable attributes
+func structuredScopeEqual(a, b *StructuredScope) bool {
+	return a.AssetIdentifier == b.AssetIdentifier &&
+		a.AssetType == b.AssetType &&
+		a.EligibleForBounty == b.EligibleForBounty &&
+		a.EligibleForSubmission == b.EligibleForSubmission &&
+		stringValue(a.Instruction) == stringValue(b.Instruction) &&
+		stringValue(a.ConfidentialityRequirement) == stringValue(b.ConfidentialityRequirement) &&
+		stringValue(a.IntegrityRequirement) == stringValue(b.IntegrityRequirement) &&
+		stringValue(a.AvailabilityRequirement) == stringValue(b.AvailabilityRequirement) &&
+		a.MaxSeverity == b.MaxSeverity &&
+		stringValue(a.Reference) == stringValue(b.Reference)
+}
+
+// PlanStructuredScopes fetches all structured scopes of the given program and plans the changes which turn them into
+// the desired ones. See DiffStructuredScopes.
+func (s *ProgramService) PlanStructuredScopes(programID string, desired []StructuredScope) (*StructuredScopePlan, error) {
+	return s.PlanStructuredScopesWithContext(context.Background(), programID, desired)
+}
+
+// PlanStructuredScopesWithContext fetches all structured scopes of the given program using the provided context and plans
+// the changes which turn them into the desired ones. See DiffStructuredScopes.
+func (s *ProgramService) PlanStructuredScopesWithContext(ctx context.Context, programID string, desired []StructuredScope) (*StructuredScopePlan, error) {
+	current, _, err := s.ListAllStructuredScopesWithContext(ctx, programID)
+	if err != nil {
+		return nil, err
+	}
+	return DiffStructuredScopes(programID, current, desired)
+}
+
+// ApplyStructuredScopePlan applies the changes of plan in order and returns the ones which were applied. It stops at the
+// first change which fails. In dry-run mode no request is sent and all changes are returned as if they were applied.
+func (s *ProgramService) ApplyStructuredScopePlan(plan *StructuredScopePlan, dryRun bool) ([]StructuredScopeChange, error) {
+	return s.ApplyStructuredScopePlanWithContext(context.Background(), plan, dryRun)
+}
+
+// ApplyStructuredScopePlanWithContext applies the changes of plan in order using the provided context and returns the ones
+// which were applied. It stops at the first change which fails. In dry-run mode no request is sent.
+func (s *ProgramService) ApplyStructuredScopePlanWithContext(ctx context.Context, plan *StructuredScopePlan, dryRun bool) ([]StructuredScopeChange, error) {
+	if dryRun {
+		return plan.Changes, nil
+	}
+	applied := make([]StructuredScopeChange, 0, len(plan.Changes))
+	for _, change := range plan.Changes {
+		var err error
+		switch change.Operation {
+		case StructuredScopeOperationCreate:
+			_, _, err = s.CreateStructuredScopeWithContext(ctx, plan.ProgramID, change.Desired)
+		case StructuredScopeOperationUpdate:
+			_, _, err = s.UpdateStructuredScopeWithContext(ctx, plan.ProgramID, change.Desired)
+		case StructuredScopeOperationArchive:
+			_, err = s.ArchiveStructuredScopeWithContext(ctx, plan.ProgramID, stringValue(change.Current.ID))
+		default:
+			err = fmt.Errorf("h1: unsupported structured scope operation %q", change.Operation)
+		}
+		if err != nil {
+			return applied, fmt.Errorf("h1: %s of %s asset %q failed: %w", change.Operation, scopeOf(change).AssetType, scopeOf(change).AssetIdentifier, err)
+		}
+		applied = append(applied, change)
+	}
+	return applied, nil
+}
+
+// scopeOf returns the scope a change is about
+func scopeOf(change StructuredScopeChange) *StructuredScope {
+	if change.Desired != nil {
+		return change.Desired
+	}
+	if change.Current != nil {
+		return change.Current
+	}
+	return &StructuredScope{}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+var currentScopes = []StructuredScope{
+	{ID: String("1"), AssetType: "URL", AssetIdentifier: "www.example.com", EligibleForBounty: true, EligibleForSubmission: true, Instruction: String("Be nice")},
+	{ID: String("2"), AssetType: "URL", AssetIdentifier: "api.example.com", EligibleForSubmission: true},
+	{ID: String("3"), AssetType: "URL", AssetIdentifier: "old.example.com", EligibleForSubmission: true},
+}
+
+var desiredScopes = []StructuredScope{
+	// Unchanged, the instruction is kept as it is not set
+	{AssetType: "URL", AssetIdentifier: "www.example.com", EligibleForBounty: true, EligibleForSubmission: true},
+	// Now eligible for bounty
+	{AssetType: "URL", AssetIdentifier: "api.example.com", EligibleForBounty: true, EligibleForSubmission: true},
+	// Same identifier, but a different asset type
+	{AssetType: "SOURCE_CODE", AssetIdentifier: "www.example.com", EligibleForSubmission: true},
+}
+
+func Test_DiffStructuredScopes(t *testing.T) {
+	plan, err := DiffStructuredScopes("1337", currentScopes, desiredScopes)
+	require.Nil(t, err)
+
+	// Verify that scopes are created, updated and archived by asset type and identifier
+	require.Len(t, plan.Changes, 3)
+	assert.Equal(t, StructuredScopeOperationUpdate, plan.Changes[0].Operation)
+	assert.Equal(t, String("2"), plan.Changes[0].Desired.ID)
+	assert.True(t, plan.Changes[0].Desired.EligibleForBounty)
+	assert.Equal(t, StructuredScopeOperationCreate, plan.Changes[1].Operation)
+	assert.Equal(t, "SOURCE_CODE", plan.Changes[1].Desired.AssetType)
+	assert.Nil(t, plan.Changes[1].Current)
+	assert.Equal(t, StructuredScopeOperationArchive, plan.Changes[2].Operation)
+	assert.Equal(t, String("3"), plan.Changes[2].Current.ID)
+	assert.Nil(t, plan.Changes[2].Desired)
+
+	// Verify that a changed instruction is an update
+	desired := []StructuredScope{currentScopes[0], currentScopes[1], currentScopes[2]}
+	desired[0].ID = nil
+	desired[0].Instruction = String("Be very nice")
+	plan, err = DiffStructuredScopes("1337", currentScopes, desired)
+	require.Nil(t, err)
+	require.Len(t, plan.Changes, 1)
+	assert.Equal(t, String("1"), plan.Changes[0].Desired.ID)
+
+	// Verify that an unset max severity keeps the current one and the plan converges once applied
+	current := []StructuredScope{currentScopes[0]}
+	current[0].MaxSeverity = "critical"
+	desired = []StructuredScope{desiredScopes[0]}
+	desired[0].EligibleForBounty = false
+	plan, err = DiffStructuredScopes("1337", current, desired)
+	require.Nil(t, err)
+	require.Len(t, plan.Changes, 1)
+	assert.Equal(t, "critical", plan.Changes[0].Desired.MaxSeverity)
+	current[0] = *plan.Changes[0].Desired
+	plan, err = DiffStructuredScopes("1337", current, desired)
+	require.Nil(t, err)
+	assert.Empty(t, plan.Changes)
+
+	// Verify that duplicate and invalid desired scopes are rejected
+	_, err = DiffStructuredScopes("1337", currentScopes, []StructuredScope{desiredScopes[0], desiredScopes[0]})
+	assert.True(t, errors.Is(err, ErrValidation))
+	_, err = DiffStructuredScopes("1337", currentScopes, []StructuredScope{{AssetType: "URL"}})
+	assert.True(t, errors.Is(err, ErrValidation))
+}
+
+func Test_ProgramService_ApplyStructuredScopePlan(t *testing.T) {
+	var requests []string
+	failArchive := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == "GET":
+			fmt.Fprint(w, `{"data":[
+				{"id":"1","type":"structured-scope","attributes":{"asset_type":"URL","asset_identifier":"www.example.com","eligible_for_bounty":true,"eligible_for_submission":true,"instruction":"Be nice"}},
+				{"id":"2","type":"structured-scope","attributes":{"asset_type":"URL","asset_identifier":"api.example.com","eligible_for_submission":true}},
+				{"id":"3","type":"structured-scope","attributes":{"asset_type":"URL","asset_identifier":"old.example.com","eligible_for_submission":true}}
+			],"links":{}}`)
+		case r.Method == "DELETE" && failArchive:
+			http.Error(w, `{"errors":[{"status":404,"title":"Not Found"}]}`, http.StatusNotFound)
+		case r.Method == "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			fmt.Fprint(w, `{"data":{"id":"4","type":"structured-scope"}}`)
+		}
+	}))
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	plan, err := c.Program.PlanStructuredScopes("1337", desiredScopes)
+	require.Nil(t, err)
+	require.Len(t, plan.Changes, 3)
+
+	// Verify that a dry run does not send any request
+	requests = nil
+	applied, err := c.Program.ApplyStructuredScopePlan(plan, true)
+	assert.Nil(t, err)
+	assert.Equal(t, plan.Changes, applied)
+	assert.Empty(t, requests)
+
+	// Verify that the changes are applied in order
+	applied, err = c.Program.ApplyStructuredScopePlan(plan, false)
+	assert.Nil(t, err)
+	assert.Len(t, applied, 3)
+	assert.Equal(t, []string{
+		"PUT /programs/1337/structured_scopes/2",
+		"POST /programs/1337/structured_scopes",
+		"DELETE /programs/1337/structured_scopes/3",
+	}, requests)
+
+	// Verify that a failing change stops the plan and reports what was applied
+	failArchive = true
+	applied, err = c.Program.ApplyStructuredScopePlan(plan, false)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Contains(t, err.Error(), `archive of URL asset "old.example.com"`)
+	assert.Len(t, applied, 2)
+}
@@ -22,6 +22,8 @@ package h1
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
 )
 
 // ReportState represent possible states for a report
@@ -38,6 +40,14 @@ const (
 	ReportStateSpam          string = "spam"
 )
 
+// ReportDisclosureStatus represent possible disclosure statuses for a report, as returned by Report.DisclosureStatus
+const (
+	ReportDisclosureStatusNotRequested string = "not-requested"
+	ReportDisclosureStatusRequested    string = "requested"
+	ReportDisclosureStatusAgreed       string = "agreed"
+	ReportDisclosureStatusDisclosed    string = "disclosed"
+)
+
 // Report represents a report.
 //
 // HackerOne API docs: https://api.hackerone.com/reference/#report
@@ -142,27 +152,35 @@ func (r *Report) UnmarshalJSON(b []byte) error {
 	r.Bounties = helper.Relationships.Bounties.Data
 	r.Summaries = helper.Relationships.Summaries.Data
 	r.StructuredScope = helper.Relationships.StructuredScope.Data
+	// Resolve the assignee eagerly so a malformed relationship surfaces here
+	if _, err := parseAssignee(r.RawAssignee); err != nil {
+		return fmt.Errorf("h1: malformed assignee of report %s: %w", stringValue(r.ID), err)
+	}
 	return nil
 }
 
-// Assignee returns returns the parsed assignee. For recognized assignee types, a value of the corresponding struct type will be returned.
-func (r *Report) Assignee() (assignee interface{}) {
-	var obj unknownResource
-	if err := json.Unmarshal(r.RawAssignee, &obj); err != nil {
-		panic(err.Error())
-	}
-	if obj.Type == nil {
+// parseAssignee parses the assignee relationship of a report
+func parseAssignee(raw json.RawMessage) (interface{}, error) {
+	return parseResource(raw, func(resourceType string) interface{} {
+		switch resourceType {
+		case UserType:
+			return &User{}
+		case GroupType:
+			return &Group{}
+		}
 		return nil
-	}
-	switch *obj.Type {
-	case UserType:
-		assignee = &User{}
-	case GroupType:
-		assignee = &Group{}
-	}
-	if err := json.Unmarshal(r.RawAssignee, &assignee); err != nil {
-		panic(err.Error())
-	}
+	})
+}
+
+// ParseAssignee returns the parsed assignee. For recognized assignee types, a value of the corresponding struct type will be returned.
+// Assignees of other types are returned as a *Resource. An error is returned if the assignee is malformed.
+func (r *Report) ParseAssignee() (interface{}, error) {
+	return parseAssignee(r.RawAssignee)
+}
+
+// Assignee returns the parsed assignee, or nil if it is malformed. See ParseAssignee.
+func (r *Report) Assignee() interface{} {
+	assignee, _ := r.ParseAssignee()
 	return assignee
 }
 
@@ -199,3 +217,46 @@ func (r *Report) Participants(internal bool) (participants []User) {
 	// Return the results
 	return participants
 }
+
+// DisclosureStatus returns the current disclosure status of the report, computed from its activities. It is one of the
+// ReportDisclosureStatus values. A report is disclosed once DisclosedAt is set or an activity shows it became public.
+// Otherwise the last request, agreement or cancellation wins, falling back to ReporterAgreedOnGoingPublicAt when the
+// activities were not included.
+func (r *Report) DisclosureStatus() string {
+	if r.DisclosedAt != nil {
+		return ReportDisclosureStatusDisclosed
+	}
+
+	// Activities are not guaranteed to be in chronological order. Activities without a timestamp are ordered first,
+	// so that any dated activity takes precedence over them.
+	activities := make([]*Activity, 0, len(r.Activities))
+	for idx := range r.Activities {
+		activities = append(activities, &r.Activities[idx])
+	}
+	sort.SliceStable(activities, func(i, j int) bool {
+		if activities[i].CreatedAt == nil || activities[j].CreatedAt == nil {
+			return activities[i].CreatedAt == nil && activities[j].CreatedAt != nil
+		}
+		return activities[i].CreatedAt.Before(activities[j].CreatedAt.Time)
+	})
+
+	status := ReportDisclosureStatusNotRequested
+	if r.ReporterAgreedOnGoingPublicAt != nil {
+		status = ReportDisclosureStatusRequested
+	}
+	for _, activity := range activities {
+		switch detail := activity.Activity().(type) {
+		case *ActivityAgreedOnGoingPublic:
+			if detail.FirstToAgree != nil && !*detail.FirstToAgree {
+				status = ReportDisclosureStatusAgreed
+			} else {
+				status = ReportDisclosureStatusRequested
+			}
+		case *ActivityCancelledDisclosureRequest:
+			status = ReportDisclosureStatusNotRequested
+		case *ActivityManuallyDisclosed, *ActivityReportBecamePublic:
+			return ReportDisclosureStatusDisclosed
+		}
+	}
+	return status
+}
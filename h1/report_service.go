@@ -21,7 +21,11 @@
 package h1
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -30,7 +34,12 @@ type ReportService service
 
 // Get fetches a Report by ID
 func (s *ReportService) Get(ID string) (*Report, *Response, error) {
-	req, err := s.client.NewRequest("GET", fmt.Sprintf("reports/%s", ID), nil)
+	return s.GetWithContext(context.Background(), ID)
+}
+
+// GetWithContext fetches a Report by ID using the provided context
+func (s *ReportService) GetWithContext(ctx context.Context, ID string) (*Report, *Response, error) {
+	req, err := s.client.NewRequestWithContext(ctx, "GET", fmt.Sprintf("reports/%s", ID), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -48,13 +57,20 @@ func (s *ReportService) Get(ID string) (*Report, *Response, error) {
 //
 // HackerOne API docs: https://api.hackerone.com/core-resources/#reports-change-state
 func (s *ReportService) ChangeState(ID, message, state string, originalID *string) (*Report, *Response, error) {
+	return s.ChangeStateWithContext(context.Background(), ID, message, state, originalID)
+}
+
+// ChangeStateWithContext transitions specified Report to a new state using the provided context
+//
+// HackerOne API docs: https://api.hackerone.com/core-resources/#reports-change-state
+func (s *ReportService) ChangeStateWithContext(ctx context.Context, ID, message, state string, originalID *string) (*Report, *Response, error) {
 	body := &StateChange{
 		Message:          message,
 		State:            state,
 		OriginalReportID: originalID,
 	}
 
-	req, err := s.client.NewRequest("POST", fmt.Sprintf("reports/%s/state_changes", ID), body)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", fmt.Sprintf("reports/%s/state_changes", ID), body)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -68,16 +84,34 @@ func (s *ReportService) ChangeState(ID, message, state string, originalID *strin
 	return rResp, resp, err
 }
 
-// CreateComment posts a new comment for specified Report
+// CreateComment posts a new comment for specified Report. Any uploads are attached to the comment.
 //
 // HackerOne API docs: https://api.hackerone.com/core-resources/#reports-create-comment
-func (s *ReportService) CreateComment(ID, message string, internal bool) (*Activity, *Response, error) {
-	body := &CreateComment{
-		Message:  message,
-		Internal: internal,
-	}
+func (s *ReportService) CreateComment(ID, message string, internal bool, uploads ...Upload) (*Activity, *Response, error) {
+	return s.CreateCommentWithContext(context.Background(), ID, message, internal, uploads...)
+}
 
-	req, err := s.client.NewRequest("POST", fmt.Sprintf("reports/%s/activities", ID), body)
+// CreateCommentWithContext posts a new comment for specified Report using the provided context. Any uploads are
+// attached to the comment, in which case the comment is sent as a multipart request.
+//
+// HackerOne API docs: https://api.hackerone.com/core-resources/#reports-create-comment
+func (s *ReportService) CreateCommentWithContext(ctx context.Context, ID, message string, internal bool, uploads ...Upload) (*Activity, *Response, error) {
+	var req *http.Request
+	var err error
+	if len(uploads) == 0 {
+		body := &CreateComment{
+			Message:  message,
+			Internal: internal,
+		}
+		req, err = s.client.NewRequestWithContext(ctx, "POST", fmt.Sprintf("reports/%s/activities", ID), body)
+	} else {
+		fields := url.Values{
+			"data[type]":                 {ActivityCommentType},
+			"data[attributes][message]":  {message},
+			"data[attributes][internal]": {strconv.FormatBool(internal)},
+		}
+		req, err = s.client.NewMultipartRequestWithContext(ctx, "POST", fmt.Sprintf("reports/%s/activities", ID), fields, "data[attributes][attachments][]", uploads)
+	}
 	if err != nil {
 		return nil, nil, err
 	}
@@ -91,16 +125,479 @@ func (s *ReportService) CreateComment(ID, message string, internal bool) (*Activ
 	return rResp, resp, err
 }
 
+// UploadAttachment uploads a file and attaches it to specified Report
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-upload-attachment
+func (s *ReportService) UploadAttachment(ID string, upload Upload) (*Attachment, *Response, error) {
+	return s.UploadAttachmentWithContext(context.Background(), ID, upload)
+}
+
+// UploadAttachmentWithContext uploads a file and attaches it to specified Report using the provided context
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-upload-attachment
+func (s *ReportService) UploadAttachmentWithContext(ctx context.Context, ID string, upload Upload) (*Attachment, *Response, error) {
+	fields := url.Values{
+		"data[type]": {AttachmentType},
+	}
+	req, err := s.client.NewMultipartRequestWithContext(ctx, "POST", fmt.Sprintf("reports/%s/attachments", ID), fields, "data[attributes][file]", []Upload{upload})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rResp := new(Attachment)
+	resp, err := s.client.Do(req, rResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rResp, resp, err
+}
+
 // UpdateReferenceID updates reference ID field of the report.
 //
 // HackerOne API docs: https://api.hackerone.com/core-resources/#reports-update-reference
 func (s *ReportService) UpdateReferenceID(ID, message, reference string) (*Report, *Response, error) {
+	return s.UpdateReferenceIDWithContext(context.Background(), ID, message, reference)
+}
+
+// UpdateReferenceIDWithContext updates reference ID field of the report using the provided context.
+//
+// HackerOne API docs: https://api.hackerone.com/core-resources/#reports-update-reference
+func (s *ReportService) UpdateReferenceIDWithContext(ctx context.Context, ID, message, reference string) (*Report, *Response, error) {
 	body := &UpdateReference{
 		Message:   message,
 		Reference: reference,
 	}
 
-	req, err := s.client.NewRequest("POST", fmt.Sprintf("reports/%s/issue_tracker_reference_id", ID), body)
+	req, err := s.client.NewRequestWithContext(ctx, "POST", fmt.Sprintf("reports/%s/issue_tracker_reference_id", ID), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rResp := new(Report)
+	resp, err := s.client.Do(req, rResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rResp, resp, err
+}
+
+// Assign assigns specified Report to a user or a group, or removes its assignee. assigneeType is one of UserType,
+// GroupType or AssigneeNobodyType, and assigneeID is ignored for the latter. The message is optional.
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-assign-report
+func (s *ReportService) Assign(ID, assigneeType, assigneeID, message string) (*Report, *Response, error) {
+	return s.AssignWithContext(context.Background(), ID, assigneeType, assigneeID, message)
+}
+
+// AssignWithContext assigns specified Report to a user or a group, or removes its assignee, using the provided context.
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-assign-report
+func (s *ReportService) AssignWithContext(ctx context.Context, ID, assigneeType, assigneeID, message string) (*Report, *Response, error) {
+	var body interface{}
+	switch assigneeType {
+	case UserType:
+		body = &AssignUser{ID: assigneeID, Message: message}
+	case GroupType:
+		body = &AssignGroup{ID: assigneeID, Message: message}
+	case AssigneeNobodyType:
+		body = &AssignNobody{Message: message}
+	default:
+		return nil, nil, fmt.Errorf("h1: unsupported assignee type %q", assigneeType)
+	}
+	if assigneeType != AssigneeNobodyType && assigneeID == "" {
+		return nil, nil, fmt.Errorf("h1: missing %s assignee ID", assigneeType)
+	}
+
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("reports/%s/assignee", ID), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rResp := new(Report)
+	resp, err := s.client.Do(req, rResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rResp, resp, err
+}
+
+// AwardBounty awards a bounty and an optional bonus to the reporter of specified Report
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-award-bounty
+func (s *ReportService) AwardBounty(ID string, amount, bonusAmount float64, message string) (*Bounty, *Response, error) {
+	return s.AwardBountyWithContext(context.Background(), ID, amount, bonusAmount, message)
+}
+
+// AwardBountyWithContext awards a bounty and an optional bonus to the reporter of specified Report using the provided context
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-award-bounty
+func (s *ReportService) AwardBountyWithContext(ctx context.Context, ID string, amount, bonusAmount float64, message string) (*Bounty, *Response, error) {
+	if err := validateBountyAmounts(amount, bonusAmount); err != nil {
+		return nil, nil, err
+	}
+	body := &AwardBounty{
+		Message:     message,
+		Amount:      amount,
+		BonusAmount: bonusAmount,
+	}
+
+	req, err := s.client.NewRequestWithContext(ctx, "POST", fmt.Sprintf("reports/%s/bounties", ID), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rResp := new(Bounty)
+	resp, err := s.client.Do(req, rResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rResp, resp, err
+}
+
+// SuggestBounty suggests a bounty and an optional bonus for specified Report
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-suggest-bounty
+func (s *ReportService) SuggestBounty(ID string, amount, bonusAmount float64, message string) (*Activity, *Response, error) {
+	return s.SuggestBountyWithContext(context.Background(), ID, amount, bonusAmount, message)
+}
+
+// SuggestBountyWithContext suggests a bounty and an optional bonus for specified Report using the provided context
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-suggest-bounty
+func (s *ReportService) SuggestBountyWithContext(ctx context.Context, ID string, amount, bonusAmount float64, message string) (*Activity, *Response, error) {
+	if err := validateBountyAmounts(amount, bonusAmount); err != nil {
+		return nil, nil, err
+	}
+	body := &SuggestBounty{
+		Message:     message,
+		Amount:      amount,
+		BonusAmount: bonusAmount,
+	}
+
+	req, err := s.client.NewRequestWithContext(ctx, "POST", fmt.Sprintf("reports/%s/bounty_suggestions", ID), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rResp := new(Activity)
+	resp, err := s.client.Do(req, rResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rResp, resp, err
+}
+
+// AwardSwag awards swag to the reporter of specified Report
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-award-swag
+func (s *ReportService) AwardSwag(ID, message string) (*Swag, *Response, error) {
+	return s.AwardSwagWithContext(context.Background(), ID, message)
+}
+
+// AwardSwagWithContext awards swag to the reporter of specified Report using the provided context
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-award-swag
+func (s *ReportService) AwardSwagWithContext(ctx context.Context, ID, message string) (*Swag, *Response, error) {
+	body := &AwardSwag{
+		Message: message,
+	}
+
+	req, err := s.client.NewRequestWithContext(ctx, "POST", fmt.Sprintf("reports/%s/swags", ID), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rResp := new(Swag)
+	resp, err := s.client.Do(req, rResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rResp, resp, err
+}
+
+// UpdateSeverity updates the severity of specified Report. The severity must either be a bare rating or also
+// carry the full set of CVSS metrics, and it is validated with Severity.Validate before being sent.
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-update-severity
+func (s *ReportService) UpdateSeverity(ID string, severity *Severity) (*Severity, *Response, error) {
+	return s.UpdateSeverityWithContext(context.Background(), ID, severity)
+}
+
+// UpdateSeverityWithContext updates the severity of specified Report using the provided context
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-update-severity
+func (s *ReportService) UpdateSeverityWithContext(ctx context.Context, ID string, severity *Severity) (*Severity, *Response, error) {
+	if err := severity.Validate(); err != nil {
+		return nil, nil, err
+	}
+	body := &UpdateSeverity{
+		Rating:             stringValue(severity.Rating),
+		AttackVector:       stringValue(severity.AttackVector),
+		AttackComplexity:   stringValue(severity.AttackComplexity),
+		PrivilegesRequired: stringValue(severity.PrivilegesRequired),
+		UserInteraction:    stringValue(severity.UserInteraction),
+		Scope:              stringValue(severity.Scope),
+		Confidentiality:    stringValue(severity.Confidentiality),
+		Integrity:          stringValue(severity.Integrity),
+		Availability:       stringValue(severity.Availability),
+	}
+
+	req, err := s.client.NewRequestWithContext(ctx, "POST", fmt.Sprintf("reports/%s/severities", ID), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rResp := new(Severity)
+	resp, err := s.client.Do(req, rResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rResp, resp, err
+}
+
+// UpdateWeakness changes the weakness of specified Report to the weakness with the given ID. The message is optional.
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-update-weakness
+func (s *ReportService) UpdateWeakness(ID, weaknessID, message string) (*Report, *Response, error) {
+	return s.UpdateWeaknessWithContext(context.Background(), ID, weaknessID, message)
+}
+
+// UpdateWeaknessWithContext changes the weakness of specified Report to the weakness with the given ID using the provided context
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-update-weakness
+func (s *ReportService) UpdateWeaknessWithContext(ctx context.Context, ID, weaknessID, message string) (*Report, *Response, error) {
+	body := &UpdateWeakness{
+		WeaknessID: weaknessID,
+		Message:    message,
+	}
+
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("reports/%s/weakness", ID), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rResp := new(Report)
+	resp, err := s.client.Do(req, rResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rResp, resp, err
+}
+
+// UpdateTitle changes the title of specified Report. The message is optional.
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-update-title
+func (s *ReportService) UpdateTitle(ID, title, message string) (*Report, *Response, error) {
+	return s.UpdateTitleWithContext(context.Background(), ID, title, message)
+}
+
+// UpdateTitleWithContext changes the title of specified Report using the provided context
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-update-title
+func (s *ReportService) UpdateTitleWithContext(ctx context.Context, ID, title, message string) (*Report, *Response, error) {
+	body := &UpdateTitle{
+		Title:   title,
+		Message: message,
+	}
+
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("reports/%s/title", ID), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rResp := new(Report)
+	resp, err := s.client.Do(req, rResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rResp, resp, err
+}
+
+// UpdateStructuredScope changes the structured scope of specified Report to the one with the given ID. The message is optional.
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-update-structured-scope
+func (s *ReportService) UpdateStructuredScope(ID, structuredScopeID, message string) (*Report, *Response, error) {
+	return s.UpdateStructuredScopeWithContext(context.Background(), ID, structuredScopeID, message)
+}
+
+// UpdateStructuredScopeWithContext changes the structured scope of specified Report to the one with the given ID using the provided context
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-update-structured-scope
+func (s *ReportService) UpdateStructuredScopeWithContext(ctx context.Context, ID, structuredScopeID, message string) (*Report, *Response, error) {
+	body := &UpdateStructuredScope{
+		StructuredScopeID: structuredScopeID,
+		Message:           message,
+	}
+
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("reports/%s/structured_scope", ID), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rResp := new(Report)
+	resp, err := s.client.Do(req, rResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rResp, resp, err
+}
+
+// CreateSummary creates a summary of specified Report. category is one of the ReportSummaryCategory values.
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-create-summary
+func (s *ReportService) CreateSummary(ID, category, content string) (*ReportSummary, *Response, error) {
+	return s.CreateSummaryWithContext(context.Background(), ID, category, content)
+}
+
+// CreateSummaryWithContext creates a summary of specified Report using the provided context
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-create-summary
+func (s *ReportService) CreateSummaryWithContext(ctx context.Context, ID, category, content string) (*ReportSummary, *Response, error) {
+	if err := validateSummary(category, content); err != nil {
+		return nil, nil, err
+	}
+	body := &CreateSummary{
+		Content:  content,
+		Category: category,
+	}
+
+	req, err := s.client.NewRequestWithContext(ctx, "POST", fmt.Sprintf("reports/%s/summaries", ID), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rResp := new(ReportSummary)
+	resp, err := s.client.Do(req, rResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rResp, resp, err
+}
+
+// UpdateSummary replaces the content of an existing summary of specified Report. category is one of the
+// ReportSummaryCategory values and has to match the category of the summary.
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-update-summary
+func (s *ReportService) UpdateSummary(ID, summaryID, category, content string) (*ReportSummary, *Response, error) {
+	return s.UpdateSummaryWithContext(context.Background(), ID, summaryID, category, content)
+}
+
+// UpdateSummaryWithContext replaces the content of an existing summary of specified Report using the provided context
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-update-summary
+func (s *ReportService) UpdateSummaryWithContext(ctx context.Context, ID, summaryID, category, content string) (*ReportSummary, *Response, error) {
+	if summaryID == "" {
+		return nil, nil, &ValidationError{Parameter: "id", Detail: "missing summary ID"}
+	}
+	if err := validateSummary(category, content); err != nil {
+		return nil, nil, err
+	}
+	body := &UpdateSummary{
+		ID:       summaryID,
+		Content:  content,
+		Category: category,
+	}
+
+	req, err := s.client.NewRequestWithContext(ctx, "PUT", fmt.Sprintf("reports/%s/summaries/%s", ID, summaryID), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rResp := new(ReportSummary)
+	resp, err := s.client.Do(req, rResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rResp, resp, err
+}
+
+// RequestDisclosure requests the disclosure of specified Report, or agrees to the reporter's request. substate is
+// DisclosureSubstateFull for a full disclosure or DisclosureSubstateNoContent for a limited one. The message is optional.
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-request-disclosure
+func (s *ReportService) RequestDisclosure(ID, substate, message string) (*Report, *Response, error) {
+	return s.RequestDisclosureWithContext(context.Background(), ID, substate, message)
+}
+
+// RequestDisclosureWithContext requests the disclosure of specified Report, or agrees to the reporter's request,
+// using the provided context
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-request-disclosure
+func (s *ReportService) RequestDisclosureWithContext(ctx context.Context, ID, substate, message string) (*Report, *Response, error) {
+	switch substate {
+	case DisclosureSubstateFull, DisclosureSubstateNoContent:
+	default:
+		return nil, nil, &ValidationError{
+			Parameter: "substate",
+			Detail:    fmt.Sprintf("%q is not a valid disclosure substate", substate),
+		}
+	}
+	body := &RequestDisclosure{
+		Substate: substate,
+		Message:  message,
+	}
+
+	req, err := s.client.NewRequestWithContext(ctx, "POST", fmt.Sprintf("reports/%s/disclosure_requests", ID), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rResp := new(Report)
+	resp, err := s.client.Do(req, rResp)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return rResp, resp, err
+}
+
+// CancelDisclosure cancels the pending disclosure request of specified Report
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-cancel-disclosure-request
+func (s *ReportService) CancelDisclosure(ID string) (*Response, error) {
+	return s.CancelDisclosureWithContext(context.Background(), ID)
+}
+
+// CancelDisclosureWithContext cancels the pending disclosure request of specified Report using the provided context
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-cancel-disclosure-request
+func (s *ReportService) CancelDisclosureWithContext(ctx context.Context, ID string) (*Response, error) {
+	req, err := s.client.NewRequestWithContext(ctx, "DELETE", fmt.Sprintf("reports/%s/disclosure_requests", ID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(req, nil)
+}
+
+// MarkManuallyDisclosed marks specified Report as disclosed outside of HackerOne. The message is optional.
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-manually-disclose
+func (s *ReportService) MarkManuallyDisclosed(ID, message string) (*Report, *Response, error) {
+	return s.MarkManuallyDisclosedWithContext(context.Background(), ID, message)
+}
+
+// MarkManuallyDisclosedWithContext marks specified Report as disclosed outside of HackerOne using the provided context
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-manually-disclose
+func (s *ReportService) MarkManuallyDisclosedWithContext(ctx context.Context, ID, message string) (*Report, *Response, error) {
+	body := &MarkManuallyDisclosed{
+		Message: message,
+	}
+
+	req, err := s.client.NewRequestWithContext(ctx, "POST", fmt.Sprintf("reports/%s/manual_disclosure", ID), body)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -154,6 +651,13 @@ type ReportListFilter struct {
 //
 // HackerOne API docs: https://api.hackerone.com/core-resources/#reports-get-all-reports
 func (s *ReportService) List(filterOpts ReportListFilter, listOpts *ListOptions) ([]Report, *Response, error) {
+	return s.ListWithContext(context.Background(), filterOpts, listOpts)
+}
+
+// ListWithContext returns Reports matching the specified criteria using the provided context
+//
+// HackerOne API docs: https://api.hackerone.com/core-resources/#reports-get-all-reports
+func (s *ReportService) ListWithContext(ctx context.Context, filterOpts ReportListFilter, listOpts *ListOptions) ([]Report, *Response, error) {
 	opts := struct {
 		Filter ReportListFilter `url:"filter,brackets"`
 	}{
@@ -162,7 +666,7 @@ func (s *ReportService) List(filterOpts ReportListFilter, listOpts *ListOptions)
 	// addOptions takes structs only so it can't fail
 	u, _ := addOptions("reports", &opts, listOpts)
 
-	req, err := s.client.NewRequest("GET", u, nil)
+	req, err := s.client.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -176,22 +680,32 @@ func (s *ReportService) List(filterOpts ReportListFilter, listOpts *ListOptions)
 	return *reports, resp, err
 }
 
+// ListIter returns an Iterator over all Reports matching the specified criteria
+//
+// HackerOne API docs: https://api.hackerone.com/core-resources/#reports-get-all-reports
+func (s *ReportService) ListIter(filterOpts ReportListFilter, opts *IteratorOptions) *Iterator[Report] {
+	return s.ListIterWithContext(context.Background(), filterOpts, opts)
+}
+
+// ListIterWithContext returns an Iterator over all Reports matching the specified criteria using the provided context
+//
+// HackerOne API docs: https://api.hackerone.com/core-resources/#reports-get-all-reports
+func (s *ReportService) ListIterWithContext(ctx context.Context, filterOpts ReportListFilter, opts *IteratorOptions) *Iterator[Report] {
+	return newIterator(ctx, s.client, func(ctx context.Context, listOpts *ListOptions) ([]Report, *Response, error) {
+		return s.ListWithContext(ctx, filterOpts, listOpts)
+	}, opts)
+}
+
 // ListAll returns all Reports matching the specified criteria
 //
 // HackerOne API docs: https://api.hackerone.com/core-resources/#reports-get-all-reports
 func (s *ReportService) ListAll(filterOpts ReportListFilter) ([]Report, *Response, error) {
-	listOpts := &ListOptions{PageSize: defaultPageSize}
-	reports := []Report{}
-	for {
-		reportList, resp, err := s.List(filterOpts, listOpts)
-		if err != nil {
-			return nil, resp, err
-		}
-		reports = append(reports, reportList...)
-		if resp.Links.Next == "" {
-			break
-		}
-		listOpts.Page = resp.Links.NextPageNumber()
-	}
-	return reports, nil, nil
+	return s.ListAllWithContext(context.Background(), filterOpts)
+}
+
+// ListAllWithContext returns all Reports matching the specified criteria, stopping between pages once ctx is done
+//
+// HackerOne API docs: https://api.hackerone.com/core-resources/#reports-get-all-reports
+func (s *ReportService) ListAllWithContext(ctx context.Context, filterOpts ReportListFilter) ([]Report, *Response, error) {
+	return collect(s.ListIterWithContext(ctx, filterOpts, nil))
 }
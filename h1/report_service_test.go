@@ -23,9 +23,14 @@ package h1
 import (
 	"github.com/stretchr/testify/assert"
 
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 )
 
@@ -137,6 +142,358 @@ func Test_ReportService_List(t *testing.T) {
 
 }
 
+func Test_ReportService_Assign(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		var payload struct {
+			Data json.RawMessage `json:"data"`
+		}
+		json.Unmarshal(body, &payload)
+		var assignee Resource
+		json.Unmarshal(payload.Data, &assignee)
+		if *assignee.Type == AssigneeNobodyType {
+			payload.Data = json.RawMessage("null")
+		}
+		fmt.Fprintf(w, `{"data":{"id":"1337","type":"report","relationships":{"assignee":{"data":%s}}}}`, payload.Data)
+	}))
+	defer server.Close()
+	c := NewClient(nil)
+	u, err := url.Parse(server.URL + "/")
+	assert.Nil(t, err)
+	c.BaseURL = u
+
+	// Verify that a report can be assigned to a user
+	report, _, err := c.Report.Assign("1337", UserType, "42", "Please have a look")
+	assert.Nil(t, err)
+	assert.Equal(t, `PUT /reports/1337/assignee {"data":{"type":"user","id":"42","attributes":{"message":"Please have a look"}}}`+"\n", requests[0])
+	assert.Equal(t, &User{ID: String("42"), Type: String(UserType)}, report.Assignee())
+
+	// Verify that a report can be assigned to a group without a message
+	report, _, err = c.Report.Assign("1337", GroupType, "7", "")
+	assert.Nil(t, err)
+	assert.Equal(t, `PUT /reports/1337/assignee {"data":{"type":"group","id":"7"}}`+"\n", requests[1])
+	assert.Equal(t, &Group{ID: String("7"), Type: String(GroupType)}, report.Assignee())
+
+	// Verify that the assignee of a report can be removed
+	report, _, err = c.Report.Assign("1337", AssigneeNobodyType, "", "Unassigning")
+	assert.Nil(t, err)
+	assert.Equal(t, `PUT /reports/1337/assignee {"data":{"type":"nobody","attributes":{"message":"Unassigning"}}}`+"\n", requests[2])
+	assert.Nil(t, report.Assignee())
+
+	// Verify that invalid assignees are rejected before sending a request
+	_, _, err = c.Report.Assign("1337", "team", "1", "")
+	assert.NotNil(t, err)
+	_, _, err = c.Report.Assign("1337", UserType, "", "")
+	assert.NotNil(t, err)
+	assert.Len(t, requests, 3)
+}
+
+// resourceServer responds to every request with the given resource fixture and records the requests it receives
+func resourceServer(t *testing.T, resource string, requests *[]string) *httptest.Server {
+	data, err := ioutil.ReadFile(resource)
+	assert.Nil(t, err)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		*requests = append(*requests, r.Method+" "+r.URL.Path+" "+string(body))
+		fmt.Fprintf(w, `{"data":%s}`, data)
+	}))
+}
+
+func Test_ReportService_AwardBounty(t *testing.T) {
+	var requests []string
+	server := resourceServer(t, "tests/resources/bounty.json", &requests)
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that the bounty is posted and returned
+	bounty, _, err := c.Report.AwardBounty("1337", 500, 50, "Thanks!")
+	assert.Nil(t, err)
+	assert.Equal(t, `POST /reports/1337/bounties {"data":{"type":"bounty","attributes":{"amount":500,"bonus_amount":50,"message":"Thanks!"}}}`+"\n", requests[0])
+	assert.Equal(t, String("1337"), bounty.ID)
+
+	// Verify that invalid amounts are rejected before sending a request
+	_, _, err = c.Report.AwardBounty("1337", -500, 0, "Thanks!")
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.Len(t, requests, 1)
+}
+
+func Test_ReportService_SuggestBounty(t *testing.T) {
+	var requests []string
+	server := resourceServer(t, "tests/resources/activity-bounty-suggested.json", &requests)
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that the suggestion is posted and the resulting activity returned
+	activity, _, err := c.Report.SuggestBounty("1337", 500, 0, "Looks like a high")
+	assert.Nil(t, err)
+	assert.Equal(t, `POST /reports/1337/bounty_suggestions {"data":{"type":"bounty-suggestion","attributes":{"amount":500,"message":"Looks like a high"}}}`+"\n", requests[0])
+	assert.Equal(t, &ActivityBountySuggested{BountyAmount: String("500"), BonusAmount: String("50")}, activity.Activity())
+
+	// Verify that invalid amounts are rejected before sending a request
+	_, _, err = c.Report.SuggestBounty("1337", 0, 0, "")
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.Len(t, requests, 1)
+}
+
+func Test_ReportService_AwardSwag(t *testing.T) {
+	var requests []string
+	server := resourceServer(t, "tests/resources/swag.json", &requests)
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that the swag is posted and returned
+	swag, _, err := c.Report.AwardSwag("1337", "Enjoy!")
+	assert.Nil(t, err)
+	assert.Equal(t, `POST /reports/1337/swags {"data":{"type":"swag","attributes":{"message":"Enjoy!"}}}`+"\n", requests[0])
+	assert.Equal(t, String("1337"), swag.ID)
+}
+
+func Test_ReportService_UpdateSeverity(t *testing.T) {
+	var requests []string
+	server := resourceServer(t, "tests/resources/severity.json", &requests)
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that a bare rating is sent on its own
+	severity, _, err := c.Report.UpdateSeverity("1337", &Severity{Rating: String(SeverityRatingLow)})
+	assert.Nil(t, err)
+	assert.Equal(t, `POST /reports/1337/severities {"data":{"type":"severity","attributes":{"rating":"low"}}}`+"\n", requests[0])
+	assert.Equal(t, String("57"), severity.ID)
+
+	// Verify that a full metric set is sent
+	_, _, err = c.Report.UpdateSeverity("1337", &Severity{
+		Rating:             String(SeverityRatingHigh),
+		AttackVector:       String(SeverityAttackVectorAdjacent),
+		AttackComplexity:   String(SeverityAttackComplexityLow),
+		PrivilegesRequired: String(SeverityPrivilegesRequiredLow),
+		UserInteraction:    String(SeverityUserInteractionRequired),
+		Scope:              String(SeverityScopeChanged),
+		Confidentiality:    String(SeverityConfidentialityLow),
+		Integrity:          String(SeverityIntegrityHigh),
+		Availability:       String(SeverityAvailabilityHigh),
+	})
+	assert.Nil(t, err)
+	assert.Contains(t, requests[1], `"attack_vector":"adjacent"`)
+	assert.Contains(t, requests[1], `"availability":"high"`)
+
+	// Verify that invalid severities are rejected before sending a request
+	_, _, err = c.Report.UpdateSeverity("1337", &Severity{Rating: String(SeverityRatingHigh), Scope: String(SeverityScopeChanged)})
+	assert.True(t, errors.Is(err, ErrValidation))
+	_, _, err = c.Report.UpdateSeverity("1337", nil)
+	var validationErr *ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) {
+		assert.Equal(t, "severity", validationErr.Parameter)
+	}
+	assert.Len(t, requests, 2)
+}
+
+func Test_ReportService_UpdateWeakness(t *testing.T) {
+	var requests []string
+	server := resourceServer(t, "tests/resources/report.json", &requests)
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that the weakness is changed and the report returned
+	report, _, err := c.Report.UpdateWeakness("1337", "42", "Not XSS after all")
+	assert.Nil(t, err)
+	assert.Equal(t, `PUT /reports/1337/weakness {"data":{"type":"weakness","attributes":{"message":"Not XSS after all","weakness_id":"42"}}}`+"\n", requests[0])
+	assert.Equal(t, String("1337"), report.ID)
+}
+
+func Test_ReportService_UpdateTitle(t *testing.T) {
+	var requests []string
+	server := resourceServer(t, "tests/resources/report.json", &requests)
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that the message is optional
+	report, _, err := c.Report.UpdateTitle("1337", "XSS in login form", "")
+	assert.Nil(t, err)
+	assert.Equal(t, `PUT /reports/1337/title {"data":{"type":"report-title","attributes":{"title":"XSS in login form"}}}`+"\n", requests[0])
+	assert.Equal(t, String("1337"), report.ID)
+}
+
+func Test_ReportService_UpdateStructuredScope(t *testing.T) {
+	var requests []string
+	server := resourceServer(t, "tests/resources/report.json", &requests)
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that the structured scope is changed and the report returned
+	report, _, err := c.Report.UpdateStructuredScope("1337", "57", "Moved to the API asset")
+	assert.Nil(t, err)
+	assert.Equal(t, `PUT /reports/1337/structured_scope {"data":{"type":"structured-scope","attributes":{"message":"Moved to the API asset","structured_scope_id":"57"}}}`+"\n", requests[0])
+	assert.Equal(t, String("1337"), report.ID)
+}
+
+func Test_ReportService_CreateSummary(t *testing.T) {
+	var requests []string
+	server := resourceServer(t, "tests/resources/report-summary.json", &requests)
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that the summary is posted and returned
+	summary, _, err := c.Report.CreateSummary("1337", ReportSummaryCategoryTeam, "There was a cross-site scripting vulnerability in our login form.")
+	assert.Nil(t, err)
+	assert.Equal(t, `POST /reports/1337/summaries {"data":{"type":"report-summary","attributes":{"category":"team","content":"There was a cross-site scripting vulnerability in our login form."}}}`+"\n", requests[0])
+	assert.Equal(t, String("1337"), summary.ID)
+	assert.Equal(t, String(ReportSummaryCategoryTeam), summary.Category)
+
+	// Verify that invalid summaries are rejected before sending a request
+	_, _, err = c.Report.CreateSummary("1337", "public", "Summary")
+	assert.True(t, errors.Is(err, ErrValidation))
+	_, _, err = c.Report.CreateSummary("1337", ReportSummaryCategoryResearcher, " ")
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.Len(t, requests, 1)
+}
+
+func Test_ReportService_UpdateSummary(t *testing.T) {
+	var requests []string
+	server := resourceServer(t, "tests/resources/report-summary.json", &requests)
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that the summary is updated and returned
+	summary, _, err := c.Report.UpdateSummary("1337", "1337", ReportSummaryCategoryTeam, "Fixed")
+	assert.Nil(t, err)
+	assert.Equal(t, `PUT /reports/1337/summaries/1337 {"data":{"type":"report-summary","id":"1337","attributes":{"category":"team","content":"Fixed"}}}`+"\n", requests[0])
+	assert.Equal(t, String("1337"), summary.ID)
+
+	// Verify that invalid summaries are rejected before sending a request
+	_, _, err = c.Report.UpdateSummary("1337", "", ReportSummaryCategoryTeam, "Fixed")
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "id", validationErr.Parameter)
+	_, _, err = c.Report.UpdateSummary("1337", "1337", "", "Fixed")
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "category", validationErr.Parameter)
+	assert.Len(t, requests, 1)
+}
+
+func Test_ReportService_RequestDisclosure(t *testing.T) {
+	var requests []string
+	server := resourceServer(t, "tests/resources/report.json", &requests)
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that a limited disclosure can be requested
+	report, _, err := c.Report.RequestDisclosure("1337", DisclosureSubstateNoContent, "")
+	assert.Nil(t, err)
+	assert.Equal(t, `POST /reports/1337/disclosure_requests {"data":{"type":"disclosure-request","attributes":{"substate":"no-content"}}}`+"\n", requests[0])
+	assert.Equal(t, String("1337"), report.ID)
+
+	// Verify that invalid substates are rejected before sending a request
+	_, _, err = c.Report.RequestDisclosure("1337", "partial", "")
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.Len(t, requests, 1)
+}
+
+func Test_ReportService_CancelDisclosure(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that an empty response is not an error
+	resp, err := c.Report.CancelDisclosure("1337")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, []string{"DELETE /reports/1337/disclosure_requests"}, requests)
+}
+
+func Test_ReportService_MarkManuallyDisclosed(t *testing.T) {
+	var requests []string
+	server := resourceServer(t, "tests/resources/report.json", &requests)
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that the report is marked as disclosed and returned
+	report, _, err := c.Report.MarkManuallyDisclosed("1337", "Published on our blog")
+	assert.Nil(t, err)
+	assert.Equal(t, `POST /reports/1337/manual_disclosure {"data":{"type":"manual-disclosure","attributes":{"message":"Published on our blog"}}}`+"\n", requests[0])
+	assert.Equal(t, String("1337"), report.ID)
+}
+
+func Test_ReportService_CreateComment(t *testing.T) {
+	var requests []*http.Request
+	var files []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+			assert.Nil(t, r.ParseMultipartForm(1<<20))
+			for _, header := range r.MultipartForm.File["data[attributes][attachments][]"] {
+				files = append(files, header.Filename)
+			}
+		}
+		requests = append(requests, r)
+		fmt.Fprint(w, `{"data":{"id":"1337","type":"activity-comment"}}`)
+	}))
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that a comment without uploads is sent as JSON:API
+	activity, _, err := c.Report.CreateComment("1337", "Thanks!", false)
+	assert.Nil(t, err)
+	assert.Equal(t, String(ActivityCommentType), activity.Type)
+	assert.Equal(t, "application/json", requests[0].Header.Get("Content-Type"))
+
+	// Verify that uploads are attached in a multipart request
+	_, _, err = c.Report.CreateComment("1337", "Logs attached", true,
+		Upload{FileName: "repro.log", ContentType: "text/plain", Reader: strings.NewReader("GET /login")},
+		Upload{FileName: "patched.png", ContentType: "image/png", Reader: strings.NewReader("png")},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, "/reports/1337/activities", requests[1].URL.Path)
+	assert.Equal(t, ActivityCommentType, requests[1].FormValue("data[type]"))
+	assert.Equal(t, "Logs attached", requests[1].FormValue("data[attributes][message]"))
+	assert.Equal(t, "true", requests[1].FormValue("data[attributes][internal]"))
+	assert.Equal(t, []string{"repro.log", "patched.png"}, files)
+}
+
+func Test_ReportService_UploadAttachment(t *testing.T) {
+	var content string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/reports/1337/attachments", r.URL.Path)
+		file, _, err := r.FormFile("data[attributes][file]")
+		if assert.Nil(t, err) {
+			data, _ := ioutil.ReadAll(file)
+			content = string(data)
+		}
+		fixture, _ := ioutil.ReadFile("tests/resources/attachment.json")
+		fmt.Fprintf(w, `{"data":%s}`, fixture)
+	}))
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+
+	// Verify that the file is streamed and the attachment returned
+	attachment, _, err := c.Report.UploadAttachment("1337", Upload{FileName: "root.rb", Reader: strings.NewReader("puts 'hi'")})
+	assert.Nil(t, err)
+	assert.Equal(t, "puts 'hi'", content)
+	assert.Equal(t, String("1337"), attachment.ID)
+
+	// Verify that an invalid upload is rejected before sending a request
+	_, _, err = c.Report.UploadAttachment("1337", Upload{FileName: "root.rb"})
+	assert.True(t, errors.Is(err, ErrValidation))
+}
+
 /*
 
 // List returns all Reports matching the specified criteria
@@ -22,7 +22,9 @@ package h1
 
 import (
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"encoding/json"
 	"testing"
 )
 
@@ -191,25 +193,61 @@ func Test_Report(t *testing.T) {
 	assigneeNil := assigneeNilReport.Assignee()
 	assert.Nil(t, assigneeNil)
 
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Report.Assignee() with invalid JSON should panic")
-			}
-		}()
-		var assigneeInvalidReport Report
-		assigneeInvalidReport.RawAssignee = []byte(`Invalid JSON`)
-		assigneeInvalidReport.Assignee()
-	}()
-
-	func() {
-		defer func() {
-			if recover() == nil {
-				assert.Fail(t, "Report.Assignee() with incorrect JSON should panic")
-			}
-		}()
-		var assigneeInvalidReport Report
-		assigneeInvalidReport.RawAssignee = []byte(`{"type": "group", "id": 123}`)
-		assigneeInvalidReport.Assignee()
-	}()
+	var assigneeInvalidReport Report
+	assigneeInvalidReport.RawAssignee = []byte(`Invalid JSON`)
+	_, err := assigneeInvalidReport.ParseAssignee()
+	assert.NotNil(t, err)
+	assert.Nil(t, assigneeInvalidReport.Assignee())
+
+	assigneeInvalidReport.RawAssignee = []byte(`{"type": "group", "id": 123}`)
+	_, err = assigneeInvalidReport.ParseAssignee()
+	assert.NotNil(t, err)
+	assert.Nil(t, assigneeInvalidReport.Assignee())
+
+	var assigneeUnknownReport Report
+	assigneeUnknownReport.RawAssignee = []byte(`{"type": "team", "id": "1337"}`)
+	assigneeUnknown, err := assigneeUnknownReport.ParseAssignee()
+	assert.Nil(t, err)
+	assert.Equal(t, &Resource{ID: String("1337"), Type: String("team")}, assigneeUnknown)
+}
+
+func Test_Report_MalformedAssignee(t *testing.T) {
+	// A malformed assignee relationship fails unmarshalling the report
+	var actual Report
+	err := json.Unmarshal([]byte(`{"id":"1337","type":"report","relationships":{"assignee":{"data":{"type":"group","id":123}}}}`), &actual)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "malformed assignee of report 1337")
+
+	// So does a malformed actor of one of its activities
+	err = json.Unmarshal([]byte(`{"id":"1337","type":"report","relationships":{"activities":{"data":[{"id":"42","type":"activity-comment","relationships":{"actor":{"data":{"type":"user","id":123}}}}]}}}`), &actual)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Error(), "malformed actor of activity 42")
+}
+
+func Test_Report_DisclosureStatus(t *testing.T) {
+	activity := func(activityType, attributes string) Activity {
+		var a Activity
+		err := json.Unmarshal([]byte(`{"id":"1","type":"`+activityType+`","attributes":{`+attributes+`}}`), &a)
+		require.Nil(t, err)
+		return a
+	}
+	requested := activity(ActivityAgreedOnGoingPublicType, `"first_to_agree":true,"created_at":"2016-02-02T04:05:06.000Z"`)
+	agreed := activity(ActivityAgreedOnGoingPublicType, `"first_to_agree":false,"created_at":"2016-02-03T04:05:06.000Z"`)
+	cancelled := activity(ActivityCancelledDisclosureRequestType, `"created_at":"2016-02-04T04:05:06.000Z"`)
+	disclosed := activity(ActivityManuallyDisclosedType, `"created_at":"2016-02-05T04:05:06.000Z"`)
+
+	// Verify that the status follows the activities in chronological order
+	assert.Equal(t, ReportDisclosureStatusNotRequested, (&Report{}).DisclosureStatus())
+	assert.Equal(t, ReportDisclosureStatusRequested, (&Report{Activities: []Activity{requested}}).DisclosureStatus())
+	assert.Equal(t, ReportDisclosureStatusAgreed, (&Report{Activities: []Activity{agreed, requested}}).DisclosureStatus())
+	assert.Equal(t, ReportDisclosureStatusNotRequested, (&Report{Activities: []Activity{cancelled, requested}}).DisclosureStatus())
+	assert.Equal(t, ReportDisclosureStatusRequested, (&Report{ReporterAgreedOnGoingPublicAt: NewTimestamp("2016-02-05T04:05:06.000Z")}).DisclosureStatus())
+	assert.Equal(t, ReportDisclosureStatusDisclosed, (&Report{Activities: []Activity{requested, agreed, disclosed}}).DisclosureStatus())
+	assert.Equal(t, ReportDisclosureStatusDisclosed, (&Report{DisclosedAt: NewTimestamp("2016-02-05T04:05:06.000Z")}).DisclosureStatus())
+
+	// Verify that activities without a timestamp are ordered before the dated ones
+	undated := activity(ActivityCancelledDisclosureRequestType, ``)
+	assert.Equal(t, ReportDisclosureStatusAgreed, (&Report{Activities: []Activity{agreed, undated, requested}}).DisclosureStatus())
+	assert.Equal(t, ReportDisclosureStatusAgreed, (&Report{Activities: []Activity{requested, agreed, undated}}).DisclosureStatus())
+	assert.Equal(t, ReportDisclosureStatusNotRequested, (&Report{Activities: []Activity{undated}}).DisclosureStatus())
 }
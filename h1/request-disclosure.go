@@ -0,0 +1,46 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+// DisclosureSubstate represent possible kinds of disclosure which can be requested for a report
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-request-disclosure
+const (
+	DisclosureSubstateFull      string = "full"
+	DisclosureSubstateNoContent string = "no-content"
+)
+
+// RequestDisclosure represents a request body for requesting or agreeing to the disclosure of a report
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-request-disclosure
+type RequestDisclosure struct {
+	Type     string `jsonapi:"primary,disclosure-request"`
+	Substate string `jsonapi:"attr,substate"`
+	Message  string `jsonapi:"attr,message,omitempty"`
+}
+
+// MarkManuallyDisclosed represents a request body for marking a report as disclosed outside of HackerOne
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-manually-disclose
+type MarkManuallyDisclosed struct {
+	Type    string `jsonapi:"primary,manual-disclosure"`
+	Message string `jsonapi:"attr,message,omitempty"`
+}
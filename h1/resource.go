@@ -20,9 +20,40 @@
 
 package h1
 
-// Used to unmarshal unknown types
-type unknownResource struct {
-	Type *string `json:"type"`
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Resource represents a JSONAPI resource of a type which is not known to this package.
+type Resource struct {
+	ID            *string         `json:"id"`
+	Type          *string         `json:"type"`
+	Attributes    json.RawMessage `json:"attributes,omitempty"`
+	Relationships json.RawMessage `json:"relationships,omitempty"`
+}
+
+// parseResource unmarshals raw into the value returned by newValue for its type. A nil value from newValue
+// means the type is unknown and a *Resource is returned instead. Missing resources are returned as nil.
+func parseResource(raw json.RawMessage, newValue func(resourceType string) interface{}) (interface{}, error) {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+	var obj Resource
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+	if obj.Type == nil {
+		return nil, nil
+	}
+	value := newValue(*obj.Type)
+	if value == nil {
+		return &obj, nil
+	}
+	if err := json.Unmarshal(raw, value); err != nil {
+		return nil, err
+	}
+	return value, nil
 }
 
 // Type represent the possible values for the "Type" attribute
@@ -40,12 +71,16 @@ const (
 	ActivityBugNotApplicableType                string = "activity-bug-not-applicable"
 	ActivityBugReopenedType                     string = "activity-bug-reopened"
 	ActivityBugResolvedType                     string = "activity-bug-resolved"
+	ActivityBugRetestingType                    string = "activity-bug-retesting"
 	ActivityBugSpamType                         string = "activity-bug-spam"
 	ActivityBugTriagedType                      string = "activity-bug-triaged"
 	ActivityCancelledDisclosureRequestType      string = "activity-cancelled-disclosure-request"
 	ActivityChangedScopeType                    string = "activity-changed-scope"
 	ActivityCommentType                         string = "activity-comment"
 	ActivityCommentsClosedType                  string = "activity-comments-closed"
+	ActivityCommentsOpenedType                  string = "activity-comments-opened"
+	ActivityCVEIDAddedType                      string = "activity-cve-id-added"
+	ActivityExternalAdvisoryAddedType           string = "activity-external-advisory-added"
 	ActivityExternalUserInvitationCancelledType string = "activity-external-user-invitation-cancelled"
 	ActivityExternalUserInvitedType             string = "activity-external-user-invited"
 	ActivityExternalUserJoinedType              string = "activity-external-user-joined"
@@ -59,9 +94,14 @@ const (
 	ActivityProgramInactiveType                 string = "activity-program-inactive"
 	ActivityReferenceIDAddedType                string = "activity-reference-id-added"
 	ActivityReportBecamePublicType              string = "activity-report-became-public"
+	ActivityReportCollaboratorInvitedType       string = "activity-report-collaborator-invited"
+	ActivityReportCollaboratorJoinedType        string = "activity-report-collaborator-joined"
+	ActivityReportRetestApprovedType            string = "activity-report-retest-approved"
+	ActivityReportRetestRejectedType            string = "activity-report-retest-rejected"
 	ActivityReportSeverityUpdatedType           string = "activity-report-severity-updated"
 	ActivityReportTitleUpdatedType              string = "activity-report-title-updated"
 	ActivityReportVulnerabilityTypesUpdatedType string = "activity-report-vulnerability-types-updated"
+	ActivityRetestUserExpiredType               string = "activity-retest-user-expired"
 	ActivitySwagAwardedType                     string = "activity-swag-awarded"
 	ActivityUserAssignedToBugType               string = "activity-user-assigned-to-bug"
 	ActivityUserBannedFromProgramType           string = "activity-user-banned-from-program"
@@ -81,6 +121,7 @@ const (
 	SwagType                                    string = "swag"
 	SeverityType                                string = "severity"
 	StateChangeType                             string = "state-change"
+	StructuredScopeType                         string = "structured-scope"
 	UserType                                    string = "user"
 	VulnerabilityTypeType                       string = "vulnerability-type"
 )
@@ -99,3 +140,11 @@ func Uint64(v uint64) *uint64 { return &v }
 
 // Float64 allocates a new float64 value to store v at and returns a pointer to it.
 func Float64(v float64) *float64 { return &v }
+
+// stringValue returns the value of s, or an empty string if it is nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
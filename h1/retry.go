@@ -0,0 +1,132 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy describes how Client.Do retries requests which failed with a 429 or a 5xx response.
+type RetryPolicy struct {
+	// Total number of attempts made for a request, including the first one.
+	MaxAttempts int
+
+	// Delay before the first retry. It doubles on every subsequent retry and a random jitter of up to half of it is subtracted.
+	BaseDelay time.Duration
+
+	// Upper bound for the delay between two attempts. A Retry-After header asking for a longer delay ends the retries.
+	MaxDelay time.Duration
+
+	// Also retry non-idempotent requests, such as POST, after a 5xx response. They may then be applied more than once.
+	// Non-idempotent requests are otherwise only retried after a 429, as the API rejected them without processing them.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a RetryPolicy suitable for most API consumers.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+	}
+}
+
+// backoff determines whether the request which resulted in resp should be attempted again, and how long to wait before doing so
+func (p *RetryPolicy) backoff(req *http.Request, resp *http.Response, attempt int) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+	case resp.StatusCode >= 500 && resp.StatusCode <= 599:
+		if !isIdempotent(req.Method) && !p.RetryNonIdempotent {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	// Honor the delay the API asked for
+	if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		if p.MaxDelay > 0 && delay > p.MaxDelay {
+			return 0, false
+		}
+		return delay, true
+	}
+
+	// Otherwise back off exponentially with jitter
+	delay := p.BaseDelay << uint(attempt-1)
+	if delay < p.BaseDelay || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	if half := int64(delay / 2); half > 0 {
+		delay -= time.Duration(rand.Int63n(half))
+	}
+	return delay, true
+}
+
+// isIdempotent reports whether a request with the given method can safely be sent more than once
+func isIdempotent(method string) bool {
+	switch method {
+	case "", "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a number of seconds or a HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if delay := date.Sub(now); delay > 0 {
+		return delay, true
+	}
+	return 0, true
+}
+
+// rewindRequest returns a copy of req with a fresh body so that it can be sent again
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("request body cannot be rewound")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	next.Body = body
+	return next, nil
+}
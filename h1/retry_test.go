@@ -0,0 +1,212 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"github.com/stretchr/testify/assert"
+
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// failingServer responds with the given status codes in order, and with an empty report once they are exhausted
+func failingServer(t *testing.T, statuses []int, headers http.Header) (*httptest.Server, *[]string) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.Nil(t, err)
+		bodies = append(bodies, string(body))
+		if len(bodies) <= len(statuses) {
+			for k, v := range headers {
+				w.Header()[k] = v
+			}
+			http.Error(w, "Oh No", statuses[len(bodies)-1])
+			return
+		}
+		io.WriteString(w, `{"data":{"id":"1337","type":"report"}}`)
+	}))
+	return server, &bodies
+}
+
+func newRetryClient(t *testing.T, server *httptest.Server, policy *RetryPolicy) *Client {
+	c := NewClient(nil)
+	u, err := url.Parse(server.URL + "/")
+	assert.Nil(t, err)
+	c.BaseURL = u
+	c.RetryPolicy = policy
+	return c
+}
+
+func Test_Client_Do_Retry(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    10 * time.Millisecond,
+	}
+
+	// Verify that requests are not retried without a policy
+	server, bodies := failingServer(t, []int{503}, nil)
+	defer server.Close()
+	_, _, err := newRetryClient(t, server, nil).Report.Get("1337")
+	assert.NotNil(t, err)
+	assert.Len(t, *bodies, 1)
+
+	// Verify that a GET recovers from server errors
+	server, bodies = failingServer(t, []int{500, 503}, nil)
+	defer server.Close()
+	report, _, err := newRetryClient(t, server, policy).Report.Get("1337")
+	assert.Nil(t, err)
+	assert.Equal(t, String("1337"), report.ID)
+	assert.Len(t, *bodies, 3)
+
+	// Verify that it gives up after MaxAttempts
+	server, bodies = failingServer(t, []int{500, 500, 500, 500}, nil)
+	defer server.Close()
+	_, resp, err := newRetryClient(t, server, policy).Report.Get("1337")
+	assert.IsType(t, &ErrorResponse{}, err)
+	assert.Equal(t, 500, resp.StatusCode)
+	assert.Len(t, *bodies, 3)
+
+	// Verify that client errors are not retried
+	server, bodies = failingServer(t, []int{404}, nil)
+	defer server.Close()
+	_, _, err = newRetryClient(t, server, policy).Report.Get("1337")
+	assert.NotNil(t, err)
+	assert.Len(t, *bodies, 1)
+}
+
+func Test_Client_Do_RetryNonIdempotent(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    10 * time.Millisecond,
+	}
+
+	// Verify that a POST is not replayed after a server error
+	server, bodies := failingServer(t, []int{502}, nil)
+	defer server.Close()
+	_, _, err := newRetryClient(t, server, policy).Report.ChangeState("1337", "Triaged!", ReportStateTriaged, nil)
+	assert.NotNil(t, err)
+	assert.Len(t, *bodies, 1)
+
+	// Verify that a POST is replayed with the same body after being rate limited
+	server, bodies = failingServer(t, []int{429}, nil)
+	defer server.Close()
+	_, _, err = newRetryClient(t, server, policy).Report.ChangeState("1337", "Triaged!", ReportStateTriaged, nil)
+	assert.Nil(t, err)
+	assert.Len(t, *bodies, 2)
+	assert.Equal(t, (*bodies)[0], (*bodies)[1])
+	assert.Contains(t, (*bodies)[1], "Triaged!")
+
+	// Verify that RetryNonIdempotent allows replaying a POST after a server error
+	retryAll := *policy
+	retryAll.RetryNonIdempotent = true
+	server, bodies = failingServer(t, []int{502}, nil)
+	defer server.Close()
+	_, _, err = newRetryClient(t, server, &retryAll).Report.ChangeState("1337", "Triaged!", ReportStateTriaged, nil)
+	assert.Nil(t, err)
+	assert.Len(t, *bodies, 2)
+	assert.Equal(t, (*bodies)[0], (*bodies)[1])
+}
+
+func Test_Client_Do_RetryAfter(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Hour,
+		MaxDelay:    2 * time.Second,
+	}
+
+	// Verify that Retry-After takes precedence over the exponential backoff
+	server, bodies := failingServer(t, []int{429}, http.Header{"Retry-After": []string{"0"}})
+	defer server.Close()
+	_, _, err := newRetryClient(t, server, policy).Report.Get("1337")
+	assert.Nil(t, err)
+	assert.Len(t, *bodies, 2)
+
+	// Verify that it gives up when asked to wait longer than MaxDelay
+	server, bodies = failingServer(t, []int{429}, http.Header{"Retry-After": []string{"60"}})
+	defer server.Close()
+	_, resp, err := newRetryClient(t, server, policy).Report.Get("1337")
+	assert.NotNil(t, err)
+	assert.Equal(t, 429, resp.StatusCode)
+	assert.Len(t, *bodies, 1)
+
+	// Verify that cancelling the context interrupts the backoff
+	policy.MaxDelay = time.Hour
+	server, bodies = failingServer(t, []int{503}, nil)
+	defer server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, _, err = newRetryClient(t, server, policy).Report.GetWithContext(ctx, "1337")
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Len(t, *bodies, 1)
+}
+
+func Test_RetryPolicy_backoff(t *testing.T) {
+	policy := &RetryPolicy{
+		MaxAttempts: 10,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    time.Second,
+	}
+	req := &http.Request{Method: "GET"}
+	resp := &http.Response{StatusCode: 503}
+
+	// Verify that the delay grows exponentially, with jitter, up to MaxDelay
+	for attempt, max := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		max *= time.Millisecond
+		delay, retry := policy.backoff(req, resp, attempt+1)
+		assert.True(t, retry)
+		assert.True(t, delay <= max, "attempt %d: %v > %v", attempt+1, delay, max)
+		assert.True(t, delay >= max/2, "attempt %d: %v < %v", attempt+1, delay, max/2)
+	}
+
+	// Verify that the last attempt is not retried
+	_, retry := policy.backoff(req, resp, 10)
+	assert.False(t, retry)
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2016, 2, 2, 4, 5, 6, 0, time.UTC)
+
+	delay, ok := parseRetryAfter("120", now)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, delay)
+
+	delay, ok = parseRetryAfter("Tue, 02 Feb 2016 04:05:36 GMT", now)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, delay)
+
+	delay, ok = parseRetryAfter("Tue, 02 Feb 2016 04:00:00 GMT", now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), delay)
+
+	_, ok = parseRetryAfter("", now)
+	assert.False(t, ok)
+
+	_, ok = parseRetryAfter("soon", now)
+	assert.False(t, ok)
+}
@@ -22,6 +22,7 @@ package h1
 
 import (
 	"encoding/json"
+	"fmt"
 )
 
 // SeverityRating represent possible severity ratings
@@ -41,16 +42,20 @@ const (
 	SeverityAttackVectorPhysical    string = "physical"
 	SeverityAttackComplexityLow     string = "low"
 	SeverityAttackComplexityHigh    string = "high"
+	SeverityPrivilegesRequiredNone  string = "none"
 	SeverityPrivilegesRequiredLow   string = "low"
 	SeverityPrivilegesRequiredHigh  string = "high"
 	SeverityUserInteractionNone     string = "none"
 	SeverityUserInteractionRequired string = "required"
 	SeverityScopeUnchanged          string = "unchanged"
 	SeverityScopeChanged            string = "changed"
+	SeverityConfidentialityNone     string = "none"
 	SeverityConfidentialityLow      string = "low"
 	SeverityConfidentialityHigh     string = "high"
+	SeverityIntegrityNone           string = "none"
 	SeverityIntegrityLow            string = "low"
 	SeverityIntegrityHigh           string = "high"
+	SeverityAvailabilityNone        string = "none"
 	SeverityAvailabilityLow         string = "low"
 	SeverityAvailabilityHigh        string = "high"
 )
@@ -93,3 +98,65 @@ func (s *Severity) UnmarshalJSON(b []byte) error {
 	*s = Severity(helper.severity)
 	return nil
 }
+
+// severityMetric describes a CVSS metric of a Severity for validation
+type severityMetric struct {
+	parameter string
+	value     *string
+	allowed   []string
+}
+
+// metrics returns the CVSS metrics of the severity along with their allowed values
+func (s *Severity) metrics() []severityMetric {
+	return []severityMetric{
+		{"attack_vector", s.AttackVector, []string{SeverityAttackVectorNetwork, SeverityAttackVectorAdjacent, SeverityAttackVectorLocal, SeverityAttackVectorPhysical}},
+		{"attack_complexity", s.AttackComplexity, []string{SeverityAttackComplexityLow, SeverityAttackComplexityHigh}},
+		{"privileges_required", s.PrivilegesRequired, []string{SeverityPrivilegesRequiredNone, SeverityPrivilegesRequiredLow, SeverityPrivilegesRequiredHigh}},
+		{"user_interaction", s.UserInteraction, []string{SeverityUserInteractionNone, SeverityUserInteractionRequired}},
+		{"scope", s.Scope, []string{SeverityScopeUnchanged, SeverityScopeChanged}},
+		{"confidentiality", s.Confidentiality, []string{SeverityConfidentialityNone, SeverityConfidentialityLow, SeverityConfidentialityHigh}},
+		{"integrity", s.Integrity, []string{SeverityIntegrityNone, SeverityIntegrityLow, SeverityIntegrityHigh}},
+		{"availability", s.Availability, []string{SeverityAvailabilityNone, SeverityAvailabilityLow, SeverityAvailabilityHigh}},
+	}
+}
+
+// Validate checks that the severity can be sent to the API. It must have a rating and either no CVSS metrics
+// or all of them, and every value must be one of the Severity* constants. A *ValidationError is returned otherwise,
+// including for a nil severity.
+func (s *Severity) Validate() error {
+	if s == nil {
+		return &ValidationError{Parameter: "severity", Detail: "missing severity"}
+	}
+	if err := validateSeverityValue("rating", s.Rating, []string{SeverityRatingNone, SeverityRatingLow, SeverityRatingMedium, SeverityRatingHigh, SeverityRatingCritical}); err != nil {
+		return err
+	}
+	metrics := s.metrics()
+	set := 0
+	for _, metric := range metrics {
+		if metric.value != nil {
+			set++
+		}
+	}
+	if set == 0 {
+		return nil
+	}
+	for _, metric := range metrics {
+		if err := validateSeverityValue(metric.parameter, metric.value, metric.allowed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSeverityValue checks that value is set to one of the allowed values
+func validateSeverityValue(parameter string, value *string, allowed []string) error {
+	if value == nil {
+		return &ValidationError{Parameter: parameter, Detail: "missing value"}
+	}
+	for _, a := range allowed {
+		if *value == a {
+			return nil
+		}
+	}
+	return &ValidationError{Parameter: parameter, Detail: fmt.Sprintf("%q is not one of %q", *value, allowed)}
+}
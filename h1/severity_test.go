@@ -23,6 +23,7 @@ package h1
 import (
 	"github.com/stretchr/testify/assert"
 
+	"errors"
 	"testing"
 )
 
@@ -48,3 +49,47 @@ func Test_Severity(t *testing.T) {
 	}
 	assert.Equal(t, expected, actual)
 }
+
+func Test_Severity_Validate(t *testing.T) {
+	// Verify that a bare rating is valid
+	assert.Nil(t, (&Severity{Rating: String(SeverityRatingNone)}).Validate())
+
+	// Verify that a full metric set is valid
+	full := Severity{
+		Rating:             String(SeverityRatingCritical),
+		AttackVector:       String(SeverityAttackVectorNetwork),
+		AttackComplexity:   String(SeverityAttackComplexityLow),
+		PrivilegesRequired: String(SeverityPrivilegesRequiredNone),
+		UserInteraction:    String(SeverityUserInteractionNone),
+		Scope:              String(SeverityScopeUnchanged),
+		Confidentiality:    String(SeverityConfidentialityHigh),
+		Integrity:          String(SeverityIntegrityHigh),
+		Availability:       String(SeverityAvailabilityNone),
+	}
+	assert.Nil(t, full.Validate())
+
+	assertInvalid := func(severity Severity, parameter string) {
+		var validationErr *ValidationError
+		err := severity.Validate()
+		if assert.True(t, errors.As(err, &validationErr), "%v", err) {
+			assert.Equal(t, parameter, validationErr.Parameter)
+		}
+	}
+
+	// Verify that the rating is required and checked
+	assertInvalid(Severity{}, "rating")
+	assertInvalid(Severity{Rating: String("severe")}, "rating")
+
+	// Verify that a partial metric set is rejected
+	partial := full
+	partial.Scope = nil
+	assertInvalid(partial, "scope")
+
+	// Verify that unknown metric values are rejected
+	invalid := full
+	invalid.AttackVector = String("remote")
+	assertInvalid(invalid, "attack_vector")
+	invalid = full
+	invalid.UserInteraction = String(SeverityUserInteractionNone + " ")
+	assertInvalid(invalid, "user_interaction")
+}
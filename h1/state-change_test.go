@@ -21,21 +21,27 @@
 package h1
 
 import (
+	"github.com/google/jsonapi"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"bytes"
 	"testing"
 )
 
-// TODO
-func Test_Weakness(t *testing.T) {
-	var actual StateChange
-	loadResource(t, &actual, "tests/resources/vulnerability-type.json")
-	expected := Weakness{
-		ID:          String("1337"),
-		Type:        String(VulnerabilityTypeType),
-		Name:        String("Cross-Site Scripting (XSS)"),
-		Description: String("Failure of a site to validate, filter, or encode user input before returning it to another user's web client."),
-		CreatedAt:   NewTimestamp("2016-02-02T04:05:06.000Z"),
-	}
-	assert.Equal(t, expected, actual)
+func Test_StateChange(t *testing.T) {
+	var actual bytes.Buffer
+	err := jsonapi.MarshalPayload(&actual, &StateChange{
+		Message:          "Duplicate of #1",
+		State:            ReportStateDuplicate,
+		OriginalReportID: String("1"),
+	})
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"data":{"type":"state-change","attributes":{"message":"Duplicate of #1","state":"duplicate","original_report_id":"1"}}}`, actual.String())
+
+	// Verify the original report is left out when not closing as duplicate
+	actual.Reset()
+	err = jsonapi.MarshalPayload(&actual, &StateChange{Message: "Triaged", State: ReportStateTriaged})
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"data":{"type":"state-change","attributes":{"message":"Triaged","state":"triaged"}}}`, actual.String())
 }
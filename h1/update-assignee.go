@@ -0,0 +1,48 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+// AssigneeNobodyType is the assignee type used to remove the assignee of a report
+const AssigneeNobodyType string = "nobody"
+
+// AssignUser represents a request body for assigning a report to a user
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-assign-report
+type AssignUser struct {
+	ID      string `jsonapi:"primary,user"`
+	Message string `jsonapi:"attr,message,omitempty"`
+}
+
+// AssignGroup represents a request body for assigning a report to a group
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-assign-report
+type AssignGroup struct {
+	ID      string `jsonapi:"primary,group"`
+	Message string `jsonapi:"attr,message,omitempty"`
+}
+
+// AssignNobody represents a request body for removing the assignee of a report
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-assign-report
+type AssignNobody struct {
+	ID      string `jsonapi:"primary,nobody"` // always empty
+	Message string `jsonapi:"attr,message,omitempty"`
+}
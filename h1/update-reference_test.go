@@ -21,21 +21,17 @@
 package h1
 
 import (
+	"github.com/google/jsonapi"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"bytes"
 	"testing"
 )
 
-// TODO
-func Test_Weakness(t *testing.T) {
-	var actual StateChange
-	loadResource(t, &actual, "tests/resources/vulnerability-type.json")
-	expected := Weakness{
-		ID:          String("1337"),
-		Type:        String(VulnerabilityTypeType),
-		Name:        String("Cross-Site Scripting (XSS)"),
-		Description: String("Failure of a site to validate, filter, or encode user input before returning it to another user's web client."),
-		CreatedAt:   NewTimestamp("2016-02-02T04:05:06.000Z"),
-	}
-	assert.Equal(t, expected, actual)
+func Test_UpdateReference(t *testing.T) {
+	var actual bytes.Buffer
+	err := jsonapi.MarshalPayload(&actual, &UpdateReference{Reference: "JIRA-1337", Message: "Filed"})
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"data":{"type":"issue-tracker-reference-id","attributes":{"reference":"JIRA-1337","message":"Filed"}}}`, actual.String())
 }
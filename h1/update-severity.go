@@ -0,0 +1,37 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+// UpdateSeverity represents a request body for updating the severity of a report
+//
+// HackerOne API docs: https://api.hackerone.com/customer-resources/#reports-update-severity
+type UpdateSeverity struct {
+	Type               string `jsonapi:"primary,severity"`
+	Rating             string `jsonapi:"attr,rating"`
+	AttackVector       string `jsonapi:"attr,attack_vector,omitempty"`
+	AttackComplexity   string `jsonapi:"attr,attack_complexity,omitempty"`
+	PrivilegesRequired string `jsonapi:"attr,privileges_required,omitempty"`
+	UserInteraction    string `jsonapi:"attr,user_interaction,omitempty"`
+	Scope              string `jsonapi:"attr,scope,omitempty"`
+	Confidentiality    string `jsonapi:"attr,confidentiality,omitempty"`
+	Integrity          string `jsonapi:"attr,integrity,omitempty"`
+	Availability       string `jsonapi:"attr,availability,omitempty"`
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Upload represents a file which is streamed to the API in a multipart request
+type Upload struct {
+	// Name of the file as shown on HackerOne
+	FileName string
+
+	// MIME type of the file. Defaults to application/octet-stream.
+	ContentType string
+
+	// Content of the file. It is read once, so requests with uploads are never retried.
+	Reader io.Reader
+}
+
+// validate checks that the upload has a file name and content
+func (u *Upload) validate() error {
+	if u.FileName == "" {
+		return &ValidationError{Parameter: "file_name", Detail: "missing upload file name"}
+	}
+	if u.Reader == nil {
+		return &ValidationError{Parameter: "file", Detail: fmt.Sprintf("missing content of upload %q", u.FileName)}
+	}
+	return nil
+}
+
+// NewMultipartRequest creates a multipart/form-data API request. A relative URL can be provided in urlStr.
+// The uploads are sent as fileField parts after the form fields, see NewMultipartRequestWithContext.
+func (c *Client) NewMultipartRequest(method, urlStr string, fields url.Values, fileField string, uploads []Upload) (*http.Request, error) {
+	return c.NewMultipartRequestWithContext(context.Background(), method, urlStr, fields, fileField, uploads)
+}
+
+// NewMultipartRequestWithContext creates a multipart/form-data API request bound to ctx. A relative URL can be provided in urlStr.
+// The body is streamed from the readers of the uploads while the request is sent, so it is never buffered in memory
+// and the request cannot be retried.
+func (c *Client) NewMultipartRequestWithContext(ctx context.Context, method, urlStr string, fields url.Values, fileField string, uploads []Upload) (*http.Request, error) {
+	rel, err := url.Parse(urlStr)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range uploads {
+		if err := uploads[idx].validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	body := newMultipartBody(fields, fileField, uploads)
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL.ResolveReference(rel).String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("User-Agent", c.UserAgent)
+	req.Header.Add("Content-Type", body.writer.FormDataContentType())
+
+	return req, nil
+}
+
+// multipartBody streams a multipart form through a pipe. Writing only starts on the first read, so that a request
+// which is never sent does not leave a goroutine behind.
+type multipartBody struct {
+	reader *io.PipeReader
+	pipe   *io.PipeWriter
+	writer *multipart.Writer
+	start  sync.Once
+	write  func() error
+}
+
+func newMultipartBody(fields url.Values, fileField string, uploads []Upload) *multipartBody {
+	reader, pipe := io.Pipe()
+	b := &multipartBody{
+		reader: reader,
+		pipe:   pipe,
+		writer: multipart.NewWriter(pipe),
+	}
+	b.write = func() error {
+		// Sort the fields to get a deterministic body
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, value := range fields[name] {
+				if err := b.writer.WriteField(name, value); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, upload := range uploads {
+			contentType := upload.ContentType
+			if contentType == "" {
+				contentType = "application/octet-stream"
+			}
+			header := make(textproto.MIMEHeader)
+			header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, escapeQuotes(fileField), escapeQuotes(upload.FileName)))
+			header.Set("Content-Type", contentType)
+			part, err := b.writer.CreatePart(header)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(part, upload.Reader); err != nil {
+				return err
+			}
+		}
+		return b.writer.Close()
+	}
+	return b
+}
+
+// Read implements io.Reader
+func (b *multipartBody) Read(p []byte) (int, error) {
+	b.start.Do(func() {
+		go func() {
+			b.pipe.CloseWithError(b.write())
+		}()
+	})
+	return b.reader.Read(p)
+}
+
+// Close implements io.Closer. It stops a pending write, which then fails with io.ErrClosedPipe.
+func (b *multipartBody) Close() error {
+	return b.reader.Close()
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// escapeQuotes escapes a value for a quoted Content-Disposition parameter, like mime/multipart does
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
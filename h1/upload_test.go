@@ -0,0 +1,110 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package h1
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func Test_Client_NewMultipartRequest(t *testing.T) {
+	c := NewClient(nil)
+
+	// Verify that invalid uploads are rejected
+	_, err := c.NewMultipartRequest("POST", "reports/1337/attachments", nil, "file", []Upload{{Reader: strings.NewReader("")}})
+	assert.True(t, errors.Is(err, ErrValidation))
+	_, err = c.NewMultipartRequest("POST", "reports/1337/attachments", nil, "file", []Upload{{FileName: "log.txt"}})
+	assert.True(t, errors.Is(err, ErrValidation))
+	_, err = c.NewMultipartRequest("POST", "%A", nil, "file", nil)
+	assert.NotNil(t, err)
+
+	// Verify that the fields and files are streamed as a multipart form
+	req, err := c.NewMultipartRequest("POST", "reports/1337/attachments", url.Values{"b": {"2"}, "a": {"1"}}, "file", []Upload{
+		{FileName: `"quoted".png`, ContentType: "image/png", Reader: strings.NewReader("png")},
+		{FileName: "log.txt", Reader: strings.NewReader("log")},
+	})
+	require.Nil(t, err)
+	assert.Nil(t, req.GetBody)
+	require.Nil(t, req.ParseMultipartForm(1<<20))
+	assert.Equal(t, "1", req.FormValue("a"))
+	assert.Equal(t, "2", req.FormValue("b"))
+	files := req.MultipartForm.File["file"]
+	require.Len(t, files, 2)
+	assert.Equal(t, `"quoted".png`, files[0].Filename)
+	assert.Equal(t, "image/png", files[0].Header.Get("Content-Type"))
+	assert.Equal(t, "log.txt", files[1].Filename)
+	assert.Equal(t, "application/octet-stream", files[1].Header.Get("Content-Type"))
+	f, err := files[1].Open()
+	require.Nil(t, err)
+	data, _ := ioutil.ReadAll(f)
+	assert.Equal(t, "log", string(data))
+
+	// Verify that a failing reader fails the body
+	req, err = c.NewMultipartRequest("POST", "reports/1337/attachments", nil, "file", []Upload{
+		{FileName: "broken.txt", Reader: io.MultiReader(strings.NewReader("x"), brokenReader{})},
+	})
+	require.Nil(t, err)
+	_, err = ioutil.ReadAll(req.Body)
+	assert.Equal(t, errBrokenReader, err)
+
+	// Verify that closing an unread body does not block
+	req, err = c.NewMultipartRequest("POST", "reports/1337/attachments", nil, "file", []Upload{{FileName: "log.txt", Reader: strings.NewReader("log")}})
+	require.Nil(t, err)
+	assert.Nil(t, req.Body.Close())
+}
+
+var errBrokenReader = errors.New("broken reader")
+
+type brokenReader struct{}
+
+func (brokenReader) Read([]byte) (int, error) {
+	return 0, errBrokenReader
+}
+
+func Test_Client_Do_MultipartNotRetried(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	c := NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+	c.RetryPolicy = &RetryPolicy{MaxAttempts: 3}
+
+	// Verify that a streamed body is sent only once, as it cannot be rewound
+	req, err := c.NewMultipartRequest("POST", "reports/1337/attachments", nil, "file", []Upload{{FileName: "log.txt", Reader: strings.NewReader("log")}})
+	require.Nil(t, err)
+	_, err = c.Do(req, nil)
+	assert.True(t, errors.Is(err, ErrRateLimited))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
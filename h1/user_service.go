@@ -21,6 +21,7 @@
 package h1
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -29,7 +30,12 @@ type UserService service
 
 // GetByUsername fetches a user by their username
 func (s *UserService) GetByUsername(username string) (*User, *Response, error) {
-	req, err := s.client.NewRequest("GET", fmt.Sprintf("users/%s", username), nil)
+	return s.GetByUsernameWithContext(context.Background(), username)
+}
+
+// GetByUsernameWithContext fetches a user by their username using the provided context
+func (s *UserService) GetByUsernameWithContext(ctx context.Context, username string) (*User, *Response, error) {
+	req, err := s.client.NewRequestWithContext(ctx, "GET", fmt.Sprintf("users/%s", username), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -26,10 +26,10 @@ import (
 	"testing"
 )
 
-func Test_VulnerabilityType(t *testing.T) {
-	var actual VulnerabilityType
+func Test_Weakness(t *testing.T) {
+	var actual Weakness
 	loadResource(t, &actual, "tests/resources/vulnerability-type.json")
-	expected := VulnerabilityType{
+	expected := Weakness{
 		ID:          String("1337"),
 		Type:        String(VulnerabilityTypeType),
 		Name:        String("Cross-Site Scripting (XSS)"),
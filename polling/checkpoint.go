@@ -0,0 +1,219 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package polling
+
+import (
+	"github.com/uber-go/hackeroni/h1"
+
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Checkpoint is the progress of a Poller which is persisted in a CheckpointStore, so that a restarted poller resumes
+// where it stopped instead of replaying or missing the events of its window.
+type Checkpoint struct {
+	// UpdatedAt of the most recent activity which was emitted
+	HighWaterMark time.Time `json:"high_water_mark"`
+
+	// Progress per report ID
+	Reports map[string]ReportCheckpoint `json:"reports"`
+}
+
+// ReportCheckpoint is the progress of a Poller on a single report
+type ReportCheckpoint struct {
+	// LastActivityAt of the report when it was last processed
+	LastActivityAt time.Time `json:"last_activity_at"`
+
+	// UpdatedAt of the most recent activity of the report which was emitted
+	ActivityUpdatedAt time.Time `json:"activity_updated_at"`
+
+	// IDs of the emitted activities which were updated at ActivityUpdatedAt
+	ActivityIDs []string `json:"activity_ids,omitempty"`
+
+	// Whether the new report was not received although some of its activities were, so it is emitted again
+	ReportPending bool `json:"report_pending,omitempty"`
+}
+
+// NewCheckpoint returns an empty Checkpoint
+func NewCheckpoint() *Checkpoint {
+	return &Checkpoint{Reports: make(map[string]ReportCheckpoint)}
+}
+
+// emitted reports whether the activity was already emitted according to the checkpoint
+func (c *ReportCheckpoint) emitted(activity *h1.Activity) bool {
+	updatedAt := activity.UpdatedAt.Time
+	if updatedAt.After(c.ActivityUpdatedAt) {
+		return false
+	}
+	if updatedAt.Before(c.ActivityUpdatedAt) {
+		return true
+	}
+	for _, id := range c.ActivityIDs {
+		if id == *activity.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// record marks the activity as emitted
+func (c *ReportCheckpoint) record(activity *h1.Activity) {
+	updatedAt := activity.UpdatedAt.Time
+	switch {
+	case updatedAt.After(c.ActivityUpdatedAt):
+		c.ActivityUpdatedAt = updatedAt
+		c.ActivityIDs = []string{*activity.ID}
+	case updatedAt.Equal(c.ActivityUpdatedAt):
+		c.ActivityIDs = append(c.ActivityIDs, *activity.ID)
+	}
+}
+
+// raise moves the high-water mark up to the given time
+func (c *Checkpoint) raise(updatedAt time.Time) {
+	if updatedAt.After(c.HighWaterMark) {
+		c.HighWaterMark = updatedAt
+	}
+}
+
+// prune forgets the reports which were last active before the given time. Reports which were not completed yet count
+// as active when their most recent activity was emitted.
+func (c *Checkpoint) prune(before time.Time) {
+	for id, report := range c.Reports {
+		if report.LastActivityAt.Before(before) && report.ActivityUpdatedAt.Before(before) {
+			delete(c.Reports, id)
+		}
+	}
+}
+
+// clone returns a deep copy of the checkpoint
+func (c *Checkpoint) clone() *Checkpoint {
+	clone := &Checkpoint{HighWaterMark: c.HighWaterMark, Reports: make(map[string]ReportCheckpoint, len(c.Reports))}
+	for id, report := range c.Reports {
+		report.ActivityIDs = append([]string(nil), report.ActivityIDs...)
+		clone.Reports[id] = report
+	}
+	return clone
+}
+
+// CheckpointStore persists the Checkpoint of a Poller. Implementations must be safe for concurrent use.
+type CheckpointStore interface {
+	// Load returns the most recently saved checkpoint, or nil if none was saved yet.
+	Load(ctx context.Context) (*Checkpoint, error)
+
+	// Save replaces the stored checkpoint.
+	Save(ctx context.Context, checkpoint *Checkpoint) error
+}
+
+// MemoryCheckpointStore is a CheckpointStore which keeps the checkpoint in memory. It survives restarts of a Poller
+// within the same process only.
+type MemoryCheckpointStore struct {
+	mu         sync.Mutex
+	checkpoint *Checkpoint
+}
+
+// NewMemoryCheckpointStore returns an empty MemoryCheckpointStore
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{}
+}
+
+// Load implements CheckpointStore
+func (s *MemoryCheckpointStore) Load(ctx context.Context) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.checkpoint == nil {
+		return nil, nil
+	}
+	return s.checkpoint.clone(), nil
+}
+
+// Save implements CheckpointStore
+func (s *MemoryCheckpointStore) Save(ctx context.Context, checkpoint *Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoint = checkpoint.clone()
+	return nil
+}
+
+// FileCheckpointStore is a CheckpointStore which keeps the checkpoint in a JSON file. The file is replaced atomically,
+// so a crash while saving leaves the previous checkpoint intact.
+type FileCheckpointStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileCheckpointStore returns a FileCheckpointStore which keeps the checkpoint at path
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// Load implements CheckpointStore
+func (s *FileCheckpointStore) Load(ctx context.Context) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := NewCheckpoint()
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, err
+	}
+	if checkpoint.Reports == nil {
+		checkpoint.Reports = make(map[string]ReportCheckpoint)
+	}
+	return checkpoint, nil
+}
+
+// Save implements CheckpointStore
+func (s *FileCheckpointStore) Save(ctx context.Context, checkpoint *Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
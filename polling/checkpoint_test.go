@@ -0,0 +1,118 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package polling
+
+import (
+	"github.com/uber-go/hackeroni/h1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_ReportCheckpoint(t *testing.T) {
+	at := time.Date(2016, 2, 2, 4, 5, 6, 0, time.UTC)
+	activity := func(id string, updatedAt time.Time) *h1.Activity {
+		return &h1.Activity{ID: h1.String(id), UpdatedAt: &h1.Timestamp{Time: updatedAt}}
+	}
+	var progress ReportCheckpoint
+
+	// Verify that activities are emitted once, including those updated at the same time
+	assert.False(t, progress.emitted(activity("1", at)))
+	progress.record(activity("1", at))
+	assert.True(t, progress.emitted(activity("1", at)))
+	assert.False(t, progress.emitted(activity("2", at)))
+	progress.record(activity("2", at))
+	assert.Equal(t, []string{"1", "2"}, progress.ActivityIDs)
+
+	// Verify that older activities count as emitted and newer ones do not
+	assert.True(t, progress.emitted(activity("0", at.Add(-time.Second))))
+	assert.False(t, progress.emitted(activity("1", at.Add(time.Second))))
+	progress.record(activity("3", at.Add(time.Second)))
+	assert.Equal(t, []string{"3"}, progress.ActivityIDs)
+}
+
+func testCheckpointStore(t *testing.T, store CheckpointStore) {
+	ctx := context.Background()
+
+	// Verify that an empty store has no checkpoint
+	checkpoint, err := store.Load(ctx)
+	require.Nil(t, err)
+	assert.Nil(t, checkpoint)
+
+	// Verify that a saved checkpoint is loaded again
+	at := time.Date(2016, 2, 2, 4, 5, 6, 0, time.UTC)
+	saved := NewCheckpoint()
+	saved.HighWaterMark = at
+	saved.Reports["1337"] = ReportCheckpoint{LastActivityAt: at, ActivityUpdatedAt: at, ActivityIDs: []string{"1", "2"}}
+	require.Nil(t, store.Save(ctx, saved))
+	checkpoint, err = store.Load(ctx)
+	require.Nil(t, err)
+	assert.True(t, at.Equal(checkpoint.HighWaterMark))
+	assert.Equal(t, []string{"1", "2"}, checkpoint.Reports["1337"].ActivityIDs)
+	assert.True(t, at.Equal(checkpoint.Reports["1337"].LastActivityAt))
+
+	// Verify that the stored checkpoint is not affected by later changes of the saved one
+	saved.Reports["1337"].ActivityIDs[0] = "3"
+	delete(saved.Reports, "1337")
+	checkpoint, err = store.Load(ctx)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"1", "2"}, checkpoint.Reports["1337"].ActivityIDs)
+}
+
+func Test_MemoryCheckpointStore(t *testing.T) {
+	testCheckpointStore(t, NewMemoryCheckpointStore())
+}
+
+func Test_FileCheckpointStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "polling-checkpoint")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "checkpoint.json")
+	testCheckpointStore(t, NewFileCheckpointStore(path))
+
+	// Verify that no temporary files are left behind
+	entries, _ := ioutil.ReadDir(dir)
+	assert.Len(t, entries, 1)
+
+	// Verify that a corrupt checkpoint fails to load
+	require.Nil(t, ioutil.WriteFile(path, []byte("{"), 0600))
+	_, err = NewFileCheckpointStore(path).Load(context.Background())
+	assert.NotNil(t, err)
+}
+
+func Test_Checkpoint_Prune(t *testing.T) {
+	at := time.Date(2016, 2, 2, 4, 5, 6, 0, time.UTC)
+	checkpoint := NewCheckpoint()
+	checkpoint.Reports["old"] = ReportCheckpoint{LastActivityAt: at.Add(-time.Hour)}
+	checkpoint.Reports["new"] = ReportCheckpoint{LastActivityAt: at}
+
+	// Verify that only reports which were last active before the given time are forgotten
+	checkpoint.prune(at.Add(-time.Minute))
+	assert.Len(t, checkpoint.Reports, 1)
+	assert.Contains(t, checkpoint.Reports, "new")
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package polling
+
+import (
+	"github.com/uber-go/hackeroni/h1"
+
+	"github.com/robmccoll/mitlru"
+
+	"sync"
+	"time"
+)
+
+// Deduper keeps track of the activities a Poller emitted, so that activities which are polled again in overlapping
+// windows are not emitted twice. Activities are identified by their ID and UpdatedAt, so an edited activity is emitted again.
+type Deduper interface {
+	// Seen reports whether the activity was already emitted
+	Seen(activity *h1.Activity) bool
+
+	// Mark records that the activity was emitted
+	Mark(activity *h1.Activity)
+}
+
+// activityKey identifies a version of an activity
+func activityKey(activity *h1.Activity) string {
+	return *activity.ID + "@" + activity.UpdatedAt.UTC().Format(time.RFC3339Nano)
+}
+
+// ttlDeduper remembers the most recently emitted activities for a limited time
+type ttlDeduper struct {
+	cache *mitlru.TTLRUCache
+}
+
+// NewTTLDeduper returns a Deduper which remembers up to size activities for ttl. This is the default of a Poller,
+// using a ttl of Interval+Window, after which an activity has left the window anyway.
+func NewTTLDeduper(size int, ttl time.Duration) Deduper {
+	return &ttlDeduper{cache: mitlru.NewTTLRUCache(size, ttl)}
+}
+
+func (d *ttlDeduper) Seen(activity *h1.Activity) bool {
+	_, seen := d.cache.Get(activityKey(activity))
+	return seen
+}
+
+func (d *ttlDeduper) Mark(activity *h1.Activity) {
+	d.cache.Add(activityKey(activity), struct{}{})
+}
+
+// idSetDeduper remembers every emitted activity
+type idSetDeduper struct {
+	mu   sync.Mutex
+	seen map[string]struct{}
+}
+
+// NewIDSetDeduper returns a Deduper which remembers every emitted activity exactly. Its memory grows with the number of
+// activities, so it suits short-lived pollers or programs with few reports.
+func NewIDSetDeduper() Deduper {
+	return &idSetDeduper{seen: make(map[string]struct{})}
+}
+
+func (d *idSetDeduper) Seen(activity *h1.Activity) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, seen := d.seen[activityKey(activity)]
+	return seen
+}
+
+func (d *idSetDeduper) Mark(activity *h1.Activity) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.seen[activityKey(activity)] = struct{}{}
+}
+
+// cursorDeduper remembers the most recent emitted activity of every report
+type cursorDeduper struct {
+	mu      sync.Mutex
+	reports map[string]*ReportCheckpoint
+}
+
+// NewCursorDeduper returns a Deduper which keeps a cursor per report, the UpdatedAt of the most recent emitted activity.
+// Activities updated before the cursor of their report count as seen, so its memory only grows with the number of reports.
+// Activities have to be marked in the order of their UpdatedAt within a report, which a Poller does whatever order
+// the API returns them in.
+func NewCursorDeduper() Deduper {
+	return &cursorDeduper{reports: make(map[string]*ReportCheckpoint)}
+}
+
+// reportID returns the ID of the report an activity belongs to
+func reportID(activity *h1.Activity) string {
+	if report := activity.Report(); report != nil && report.ID != nil {
+		return *report.ID
+	}
+	return ""
+}
+
+func (d *cursorDeduper) Seen(activity *h1.Activity) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	cursor, ok := d.reports[reportID(activity)]
+	return ok && cursor.emitted(activity)
+}
+
+func (d *cursorDeduper) Mark(activity *h1.Activity) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	cursor, ok := d.reports[reportID(activity)]
+	if !ok {
+		cursor = &ReportCheckpoint{}
+		d.reports[reportID(activity)] = cursor
+	}
+	cursor.record(activity)
+}
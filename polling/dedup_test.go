@@ -0,0 +1,63 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package polling
+
+import (
+	"github.com/uber-go/hackeroni/h1"
+
+	"github.com/stretchr/testify/assert"
+
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func Test_Dedupers(t *testing.T) {
+	at := time.Date(2016, 2, 2, 4, 5, 6, 0, time.UTC)
+	var report h1.Report
+	err := json.Unmarshal([]byte(`{"id":"1337","type":"report","relationships":{"activities":{"data":[
+		{"id":"1","type":"activity-comment","attributes":{"updated_at":"2016-02-02T04:05:06Z"}},
+		{"id":"2","type":"activity-comment","attributes":{"updated_at":"2016-02-02T04:05:06Z"}}
+	]}}}`), &report)
+	assert.Nil(t, err)
+	first, second := report.Activities[0], report.Activities[1]
+	edited := first
+	edited.UpdatedAt = &h1.Timestamp{Time: at.Add(time.Minute)}
+
+	for name, deduper := range map[string]Deduper{
+		"ttl":    NewTTLDeduper(100, time.Hour),
+		"id set": NewIDSetDeduper(),
+		"cursor": NewCursorDeduper(),
+	} {
+		// Verify that only marked activities are seen
+		assert.False(t, deduper.Seen(&first), name)
+		deduper.Mark(&first)
+		assert.True(t, deduper.Seen(&first), name)
+		assert.False(t, deduper.Seen(&second), name)
+		deduper.Mark(&second)
+		assert.True(t, deduper.Seen(&second), name)
+
+		// Verify that an edited activity is not seen until it is marked
+		assert.False(t, deduper.Seen(&edited), name)
+		deduper.Mark(&edited)
+		assert.True(t, deduper.Seen(&edited), name)
+	}
+}
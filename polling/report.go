@@ -23,110 +23,468 @@ package polling
 import (
 	"github.com/uber-go/hackeroni/h1"
 
-	"github.com/robmccoll/mitlru"
-
+	"context"
+	"errors"
+	"sort"
+	"sync/atomic"
 	"time"
 )
 
-// pollingCache is used to track a given
-type pollingCache struct {
-	Client             *h1.Client           // The h1.Client to use when making requests
-	Filter               h1.ReportListFilter // The h1.ReportListOptions to use when making requests
-	Window             time.Duration        // How long to look back, recommended 2*Interval
-	ReportLastActivity map[string]time.Time // The last activity we know about on that report
-	ActivitySeen       *mitlru.TTLRUCache   // If we've seen this particular activity id or not
-	ErrorChan          chan error
-	ReportChan         chan *h1.Report
-	ActivityChan       chan h1.Activity
+// ErrAlreadyRunning is returned by Poller.Run when the poller was already started
+var ErrAlreadyRunning = errors.New("polling: poller already running")
+
+// ackInterval is how often Poller.Run checks which buffered events the consumer received
+const ackInterval = 10 * time.Millisecond
+
+// Options configures a Poller
+type Options struct {
+	// How often to poll. Defaults to one minute.
+	Interval time.Duration
+
+	// How far to look back on every poll. Defaults to 2*Interval.
+	Window time.Duration
+
+	// Number of events each channel buffers before a send blocks. Zero makes the channels unbuffered.
+	BufferSize int
+
+	// How long the events of the report which is being processed when the context of Run is done keep waiting for
+	// the consumer. Zero abandons them right away.
+	DrainTimeout time.Duration
+
+	// Keep polling when an error cannot be sent right away. Errors are then dropped instead of blocking
+	// the whole cycle until the consumer reads them from the error channel.
+	ContinueOnError bool
+
+	// Where the progress is persisted, so that a restarted poller resumes where it stopped. The progress of an event
+	// is persisted as soon as it was received from its channel. Defaults to a MemoryCheckpointStore.
+	CheckpointStore CheckpointStore
+
+	// How activities which are polled again in overlapping windows are recognized.
+	// Defaults to a NewTTLDeduper remembering 100000 activities for Interval+Window.
+	Deduper Deduper
+}
+
+// Poller polls for new reports and activities and emits them on its channels
+type Poller struct {
+	client          *h1.Client          // The h1.Client to use when making requests
+	filter          h1.ReportListFilter // The h1.ReportListFilter to use when making requests
+	interval        time.Duration
+	window          time.Duration // How long to look back, recommended 2*Interval
+	drainTimeout    time.Duration // How long blocked sends wait for the consumer once Run is stopped
+	continueOnError bool
+	checkpointStore CheckpointStore
+	checkpoint      *Checkpoint          // The progress on every report we know about, including unreceived events
+	delivered       *Checkpoint          // The progress of the received events, which is what gets persisted
+	reportAcks      []delivery           // Emitted reports which may not have been received yet, oldest first
+	activityAcks    []delivery           // Emitted activities which may not have been received yet, oldest first
+	outstanding     map[string]int       // Number of emitted events per report ID which were not received yet
+	completions     map[string]time.Time // LastActivityAt of the reports which are complete once their events were received
+	unsaved         bool                 // Whether delivered has progress which was not persisted yet
+	resumed         bool                 // Whether the next poll is the first one after loading the checkpoint
+	deduper         Deduper              // Which activities we have emitted
+	errorChan       chan error
+	reportChan      chan *h1.Report
+	activityChan    chan h1.Activity
+	running         int32
+}
+
+// NewPoller returns a Poller for the reports matching filter. If nil opts are provided, the defaults are used.
+func NewPoller(client *h1.Client, filter h1.ReportListFilter, opts *Options) *Poller {
+	var o Options
+	if opts != nil {
+		o = *opts
+	}
+	if o.Interval <= 0 {
+		o.Interval = time.Minute
+	}
+	if o.Window <= 0 {
+		o.Window = 2 * o.Interval
+	}
+	if o.BufferSize < 0 {
+		o.BufferSize = 0
+	}
+	if o.DrainTimeout < 0 {
+		o.DrainTimeout = 0
+	}
+	if o.CheckpointStore == nil {
+		o.CheckpointStore = NewMemoryCheckpointStore()
+	}
+	if o.Deduper == nil {
+		// Expire known activities after the interval+window
+		o.Deduper = NewTTLDeduper(100000, o.Interval+o.Window)
+	}
+	return &Poller{
+		client:          client,
+		filter:          filter,
+		interval:        o.Interval,
+		window:          o.Window,
+		drainTimeout:    o.DrainTimeout,
+		continueOnError: o.ContinueOnError,
+		checkpointStore: o.CheckpointStore,
+		checkpoint:      NewCheckpoint(),
+		delivered:       NewCheckpoint(),
+		outstanding:     make(map[string]int),
+		completions:     make(map[string]time.Time),
+		deduper:         o.Deduper,
+		errorChan:       make(chan error, o.BufferSize),
+		reportChan:      make(chan *h1.Report, o.BufferSize),
+		activityChan:    make(chan h1.Activity, o.BufferSize),
+	}
+}
+
+// Errors returns the channel on which errors encountered while polling are emitted. It is closed when Run returns.
+func (p *Poller) Errors() <-chan error {
+	return p.errorChan
+}
+
+// Reports returns the channel on which new reports are emitted. It is closed when Run returns.
+func (p *Poller) Reports() <-chan *h1.Report {
+	return p.reportChan
+}
+
+// Activities returns the channel on which new activities are emitted. It is closed when Run returns.
+func (p *Poller) Activities() <-chan h1.Activity {
+	return p.activityChan
+}
+
+// Run loads the checkpoint, polls immediately and then at every interval until ctx is done, and returns the error of ctx
+// or of loading the checkpoint. Once ctx is done no new request is made, and the events of the report which is
+// being processed as well as the buffered events keep waiting for the consumer for up to Options.DrainTimeout. Events
+// which were not received by then are taken back from the channel buffers, and all channels are closed.
+//
+// Every event is delivered exactly once across restarts: the progress of an event is persisted once it was received,
+// and the events which were abandoned or taken back are emitted again by a restarted poller. Only a crash between
+// receiving an event and persisting its progress emits it again. A Poller can only be run once.
+func (p *Poller) Run(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&p.running, 0, 1) {
+		return ErrAlreadyRunning
+	}
+	defer func() {
+		close(p.errorChan)
+		close(p.reportChan)
+		close(p.activityChan)
+	}()
+
+	checkpoint, err := p.checkpointStore.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if checkpoint != nil {
+		p.checkpoint = checkpoint
+		p.delivered = checkpoint.clone()
+		p.resumed = true
+	}
+
+	drain, stop := drainContext(ctx, p.drainTimeout)
+	defer stop()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	acks := time.NewTicker(ackInterval)
+	defer acks.Stop()
+	for {
+		p.update(ctx, drain)
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				p.flush(ctx, drain)
+				return ctx.Err()
+			case <-ticker.C:
+				break wait
+			case <-acks.C:
+				p.settle(ctx)
+			}
+		}
+	}
 }
 
 // Start begins polling for events. It returns an error, report and activity channel which emit their respective objects when they occur
+//
+// Deprecated: Start can not be stopped. Use NewPoller and Poller.Run instead.
 func Start(client *h1.Client, filter h1.ReportListFilter, interval time.Duration, window time.Duration) (chan error, chan *h1.Report, chan h1.Activity) {
-	// Create a cache
-	cache := pollingCache{
-		Client:             client,
-		Filter:               filter,
-		Window:             window,
-		ReportLastActivity: make(map[string]time.Time),
-		ActivitySeen:       mitlru.NewTTLRUCache(100000, interval + window), // Expire known activities after the interval+window
-		ErrorChan:          make(chan error),
-		ReportChan:         make(chan *h1.Report),
-		ActivityChan:       make(chan h1.Activity),
-	}
-
-	// Start polling at the interval in the background
-	go func(pollInterval time.Duration) {
-		cache.update()
-		// Loop the provided interval
-		for range time.Tick(pollInterval) {
-			cache.update()
+	p := NewPoller(client, filter, &Options{Interval: interval, Window: window})
+	go p.Run(context.Background())
+	return p.errorChan, p.reportChan, p.activityChan
+}
+
+// drainContext returns a context which is done once timeout has passed after ctx is done
+func drainContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	drain, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-drain.Done():
+			return
 		}
-	}(interval)
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			cancel()
+		case <-drain.Done():
+		}
+	}()
+	return drain, cancel
+}
 
-	// Return the channels
-	return cache.ErrorChan, cache.ReportChan, cache.ActivityChan
+// sendError emits an error, dropping it if it can't be sent right away and the poller continues on errors.
+// It returns false if ctx is done.
+func (p *Poller) sendError(ctx context.Context, err error) bool {
+	if p.continueOnError {
+		select {
+		case p.errorChan <- err:
+		default:
+		}
+		return ctx.Err() == nil
+	}
+	select {
+	case p.errorChan <- err:
+		return true
+	case <-ctx.Done():
+		return false
+	}
 }
 
-// Perform a poll
-func (c *pollingCache) update() {
-	// We want all reports updated since now minus the window
-	updatedAt := time.Now().UTC().Add(-c.Window)
+// sendReport emits a report. It returns false if it was abandoned because drain is done.
+func (p *Poller) sendReport(drain context.Context, report *h1.Report) bool {
+	select {
+	case p.reportChan <- report:
+		return true
+	case <-drain.Done():
+		return false
+	}
+}
+
+// sendActivity emits an activity. It returns false if it was abandoned because drain is done.
+func (p *Poller) sendActivity(drain context.Context, activity h1.Activity) bool {
+	select {
+	case p.activityChan <- activity:
+		return true
+	case <-drain.Done():
+		return false
+	}
+}
+
+// Perform a poll. Requests stop when ctx is done, while the events of the report being processed are still sent
+// until drain is done.
+func (p *Poller) update(ctx, drain context.Context) {
+	// We want all reports updated since now minus the window, or since the last emitted activity if we were stopped for longer
+	updatedAt := time.Now().UTC().Add(-p.window)
+	if p.resumed && !p.checkpoint.HighWaterMark.IsZero() && p.checkpoint.HighWaterMark.Before(updatedAt) {
+		updatedAt = p.checkpoint.HighWaterMark
+	}
+	p.resumed = false
 
 	// Loop all pages to get the reports
-	var allReports []h1.Report
-	var listOptions h1.ListOptions
-	filter := c.Filter
+	filter := p.filter
 	filter.LastActivityAtGreaterThan = updatedAt
-	for {
-		reports, resp, err := c.Client.Report.List(filter, &listOptions)
-		if err != nil {
-			c.ErrorChan <- err
-			return
-		}
-		allReports = append(allReports, reports...)
-		if resp.Links.Next == "" {
-			break
+	allReports, _, err := p.client.Report.ListAllWithContext(ctx, filter)
+	if err != nil {
+		if ctx.Err() == nil {
+			p.sendError(ctx, err)
 		}
-		listOptions.Page = resp.Links.NextPageNumber()
+		return
 	}
 
 	// Loop each updated report
 	for _, report := range allReports {
-		// Get the time we last saw that report
-		lastActivityAt, seen := c.ReportLastActivity[*report.ID]
+		// Get the progress we made on that report
+		progress, seen := p.checkpoint.Reports[*report.ID]
 		// If we've seen it and the last activity updated time is equal, skip it
-		if seen && lastActivityAt.Equal(report.LastActivityAt.Time) {
+		if seen && progress.LastActivityAt.Equal(report.LastActivityAt.Time) {
 			continue
 		}
-		c.ReportLastActivity[*report.ID] = report.LastActivityAt.Time
+		listedLastActivityAt := report.LastActivityAt.Time
 
 		// In order to check the activities we have to pull the full report
-		report, _, err := c.Client.Report.Get(*report.ID)
+		report, _, err := p.client.Report.GetWithContext(ctx, *report.ID)
 		if err != nil {
-			c.ErrorChan <- err
+			if ctx.Err() != nil || !p.sendError(ctx, err) {
+				return
+			}
 			continue
 		}
 
-		// If we hadn't seen the report before, emit the event
-		if !seen && report.CreatedAt.After(updatedAt) {
-			c.ReportChan <- report
+		// If we hadn't seen the report before, emit the event. A report which was not received although some of its
+		// activities were is emitted again.
+		reportID := *report.ID
+		newReport := (!seen && report.CreatedAt.After(updatedAt)) || progress.ReportPending
+		if newReport {
+			if !p.sendReport(drain, report) {
+				return
+			}
+			progress.ReportPending = false
+			p.emitted(&p.reportAcks, reportID, func(c *Checkpoint) {
+				progress := c.Reports[reportID]
+				progress.ReportPending = false
+				c.Reports[reportID] = progress
+			})
+			p.settle(ctx)
 		}
 
-		// Loop all activity in the report
-		for _, activity := range report.Activities {
+		// Loop all activity in the report, oldest first as the API does not guarantee an order
+		activities := append([]h1.Activity(nil), report.Activities...)
+		sort.SliceStable(activities, func(i, j int) bool {
+			if !activities[i].UpdatedAt.Equal(activities[j].UpdatedAt.Time) {
+				return activities[i].UpdatedAt.Before(activities[j].UpdatedAt.Time)
+			}
+			return *activities[i].ID < *activities[j].ID
+		})
+		completed := true
+		for _, activity := range activities {
 			// If the activity was last updated before the time we updated at, ignore it
 			if activity.UpdatedAt.Time.Before(updatedAt) {
 				continue
 			}
 
-			// If we have seen the activity before, ignore it
-			_, seen := c.ActivitySeen.Get(*activity.ID)
-			if seen {
+			// If we have emitted the activity before, ignore it
+			if progress.emitted(&activity) {
+				continue
+			}
+			if p.deduper.Seen(&activity) {
 				continue
 			}
 
 			// Emit the activity
-			c.ActivityChan <- activity
+			if !p.sendActivity(drain, activity) {
+				completed = false
+				break
+			}
+			p.deduper.Mark(&activity)
+			progress.record(&activity)
+			p.checkpoint.raise(activity.UpdatedAt.Time)
+			activity := activity
+			p.emitted(&p.activityAcks, reportID, func(c *Checkpoint) {
+				progress, ok := c.Reports[reportID]
+				if !ok && newReport {
+					progress.ReportPending = true
+				}
+				progress.record(&activity)
+				c.Reports[reportID] = progress
+				c.raise(activity.UpdatedAt.Time)
+			})
+			p.settle(ctx)
+		}
+
+		// Only complete the report once all of its activities were emitted, so that the rest is emitted after a restart
+		if completed {
+			progress.LastActivityAt = listedLastActivityAt
+			p.complete(reportID, listedLastActivityAt)
+		}
+		p.checkpoint.Reports[reportID] = progress
+		if !p.settle(ctx) || !completed {
+			return
+		}
+	}
+}
+
+// delivery is an emitted event whose progress is recorded once it was received
+type delivery struct {
+	reportID string
+	record   func(c *Checkpoint)
+}
+
+// emitted queues the progress of an event which was sent on the channel of the given queue
+func (p *Poller) emitted(queue *[]delivery, reportID string, record func(c *Checkpoint)) {
+	*queue = append(*queue, delivery{reportID: reportID, record: record})
+	p.outstanding[reportID]++
+}
+
+// complete records that all activities of a report up to lastActivityAt were emitted. It is persisted once they were
+// received, so that a restarted poller processes the report again otherwise.
+func (p *Poller) complete(reportID string, lastActivityAt time.Time) {
+	if p.outstanding[reportID] > 0 {
+		p.completions[reportID] = lastActivityAt
+		return
+	}
+	progress := p.delivered.Reports[reportID]
+	progress.LastActivityAt = lastActivityAt
+	p.delivered.Reports[reportID] = progress
+	p.unsaved = true
+}
+
+// acknowledge records the progress of the emitted events which were received, given how many events of each channel
+// were not. Channels are FIFO, so those are the most recently emitted ones.
+func (p *Poller) acknowledge(unreceivedReports, unreceivedActivities int) {
+	for _, acks := range []struct {
+		queue      *[]delivery
+		unreceived int
+	}{
+		{&p.reportAcks, unreceivedReports},
+		{&p.activityAcks, unreceivedActivities},
+	} {
+		received := len(*acks.queue) - acks.unreceived
+		if received <= 0 {
+			continue
+		}
+		for _, event := range (*acks.queue)[:received] {
+			event.record(p.delivered)
+			p.unsaved = true
+			if p.outstanding[event.reportID]--; p.outstanding[event.reportID] > 0 {
+				continue
+			}
+			delete(p.outstanding, event.reportID)
+			if lastActivityAt, ok := p.completions[event.reportID]; ok {
+				delete(p.completions, event.reportID)
+				p.complete(event.reportID, lastActivityAt)
+			}
+		}
+		*acks.queue = append((*acks.queue)[:0], (*acks.queue)[received:]...)
+	}
+}
+
+// settle records and persists the progress of the emitted events which were received. It returns false if ctx is done.
+func (p *Poller) settle(ctx context.Context) bool {
+	p.acknowledge(len(p.reportChan), len(p.activityChan))
+	return p.save(ctx)
+}
+
+// save persists the progress of the received events, forgetting reports which are no longer in the window. It returns
+// false if ctx is done.
+func (p *Poller) save(ctx context.Context) bool {
+	before := time.Now().UTC().Add(-p.window)
+	if !p.checkpoint.HighWaterMark.IsZero() && p.checkpoint.HighWaterMark.Before(before) {
+		before = p.checkpoint.HighWaterMark
+	}
+	p.checkpoint.prune(before.Add(-p.window))
+	if !p.unsaved {
+		return ctx.Err() == nil
+	}
+	p.delivered.prune(before.Add(-p.window))
+	p.unsaved = false
+	// Save even if ctx is done, as the events up to now were received
+	if err := p.checkpointStore.Save(context.Background(), p.delivered); err != nil {
+		return p.sendError(ctx, err)
+	}
+	return ctx.Err() == nil
+}
+
+// flush waits until the buffered events were received or drain is done, takes back the events which were not received
+// so that a restarted poller emits them again, and persists the progress of the received ones
+func (p *Poller) flush(ctx, drain context.Context) {
+	ticker := time.NewTicker(ackInterval)
+	defer ticker.Stop()
+wait:
+	for len(p.reportChan) > 0 || len(p.activityChan) > 0 {
+		select {
+		case <-drain.Done():
+			break wait
+		case <-ticker.C:
+			p.settle(ctx)
+		}
+	}
+	var reports, activities int
+	for taken := true; taken; {
+		select {
+		case <-p.reportChan:
+			reports++
+		case <-p.activityChan:
+			activities++
+		default:
+			taken = false
 		}
 	}
+	p.acknowledge(reports, activities)
+	p.save(ctx)
 }
@@ -0,0 +1,462 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package polling
+
+import (
+	"github.com/uber-go/hackeroni/h1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeProgram serves reports and their activities, which can be changed between polls
+type fakeProgram struct {
+	mu      sync.Mutex
+	reports map[string]map[string]time.Time // Activity update times per report ID
+	created time.Time
+	failGet bool
+
+	newestFirst bool // Whether activities are served newest first instead of by ID
+}
+
+func newFakeProgram(t *testing.T) (*fakeProgram, *h1.Client) {
+	f := &fakeProgram{reports: make(map[string]map[string]time.Time), created: time.Now().UTC()}
+	server := httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(server.Close)
+	c := h1.NewClient(nil)
+	c.BaseURL, _ = url.Parse(server.URL + "/")
+	c.RetryPolicy = nil
+	return f, c
+}
+
+// setActivity adds or edits an activity of a report
+func (f *fakeProgram) setActivity(reportID, activityID string, updatedAt time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.reports[reportID] == nil {
+		f.reports[reportID] = make(map[string]time.Time)
+	}
+	f.reports[reportID][activityID] = updatedAt
+}
+
+func (f *fakeProgram) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	format := func(t time.Time) string { return t.Format(time.RFC3339Nano) }
+	report := func(id string, withActivities bool) string {
+		var last time.Time
+		var ids []string
+		for activityID, updatedAt := range f.reports[id] {
+			if updatedAt.After(last) {
+				last = updatedAt
+			}
+			ids = append(ids, activityID)
+		}
+		sort.Strings(ids)
+		if f.newestFirst {
+			sort.SliceStable(ids, func(i, j int) bool { return f.reports[id][ids[i]].After(f.reports[id][ids[j]]) })
+		}
+		var activities []string
+		for _, activityID := range ids {
+			activities = append(activities, fmt.Sprintf(`{"id":%q,"type":"activity-comment","attributes":{"internal":false,"created_at":%q,"updated_at":%q}}`,
+				activityID, format(f.created), format(f.reports[id][activityID])))
+		}
+		relationships := ""
+		if withActivities {
+			relationships = fmt.Sprintf(`,"relationships":{"activities":{"data":[%s]}}`, strings.Join(activities, ","))
+		}
+		return fmt.Sprintf(`{"id":%q,"type":"report","attributes":{"created_at":%q,"last_activity_at":%q}%s}`, id, format(f.created), format(last), relationships)
+	}
+
+	if r.URL.Path == "/reports" {
+		var reports []string
+		for id := range f.reports {
+			reports = append(reports, report(id, false))
+		}
+		sort.Strings(reports)
+		fmt.Fprintf(w, `{"data":[%s],"links":{}}`, strings.Join(reports, ","))
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, "/reports/")
+	if f.failGet || f.reports[id] == nil {
+		http.Error(w, `{"errors":[{"status":404,"title":"Not Found"}]}`, http.StatusNotFound)
+		return
+	}
+	fmt.Fprintf(w, `{"data":%s}`, report(id, true))
+}
+
+// poll performs a single poll and returns the IDs of the emitted reports and activities
+func poll(t *testing.T, p *Poller) (reports []string, activities []string, errs []error) {
+	p.update(context.Background(), context.Background())
+	for {
+		select {
+		case report := <-p.reportChan:
+			reports = append(reports, *report.ID)
+		case activity := <-p.activityChan:
+			activities = append(activities, *activity.Report().ID+"/"+*activity.ID)
+		case err := <-p.errorChan:
+			errs = append(errs, err)
+		default:
+			return reports, activities, errs
+		}
+	}
+}
+
+func Test_Poller_OverlappingWindows(t *testing.T) {
+	for name, deduper := range map[string]Deduper{
+		"ttl":    NewTTLDeduper(100, time.Hour),
+		"id set": NewIDSetDeduper(),
+		"cursor": NewCursorDeduper(),
+	} {
+		f, c := newFakeProgram(t)
+		p := NewPoller(c, h1.ReportListFilter{}, &Options{Interval: time.Minute, BufferSize: 10, Deduper: deduper})
+		now := time.Now().UTC()
+		f.setActivity("1", "1", now.Add(-30*time.Second))
+
+		// Verify that a new report and its activity are emitted
+		reports, activities, errs := poll(t, p)
+		assert.Empty(t, errs, name)
+		assert.Equal(t, []string{"1"}, reports, name)
+		assert.Equal(t, []string{"1/1"}, activities, name)
+
+		// Verify that activities which are still in the window are not emitted again when the report changes
+		f.setActivity("1", "2", now.Add(-20*time.Second))
+		reports, activities, _ = poll(t, p)
+		assert.Empty(t, reports, name)
+		assert.Equal(t, []string{"1/2"}, activities, name)
+
+		// Verify that nothing is emitted when nothing changed
+		reports, activities, _ = poll(t, p)
+		assert.Empty(t, reports, name)
+		assert.Empty(t, activities, name)
+	}
+}
+
+func Test_Poller_DeduperWithoutCheckpoint(t *testing.T) {
+	f, c := newFakeProgram(t)
+	p := NewPoller(c, h1.ReportListFilter{}, &Options{Interval: time.Minute, BufferSize: 10})
+	now := time.Now().UTC()
+	f.setActivity("1", "1", now.Add(-30*time.Second))
+	_, activities, _ := poll(t, p)
+	assert.Equal(t, []string{"1/1"}, activities)
+
+	// Verify that the deduper alone recognizes emitted activities, e.g. after the checkpoint was lost
+	p.checkpoint = NewCheckpoint()
+	f.setActivity("1", "2", now.Add(-20*time.Second))
+	_, activities, _ = poll(t, p)
+	assert.Equal(t, []string{"1/2"}, activities)
+}
+
+func Test_Poller_EditedActivities(t *testing.T) {
+	f, c := newFakeProgram(t)
+	p := NewPoller(c, h1.ReportListFilter{}, &Options{Interval: time.Minute, BufferSize: 10})
+	now := time.Now().UTC()
+	f.setActivity("1", "1", now.Add(-30*time.Second))
+	f.setActivity("1", "2", now.Add(-30*time.Second))
+	_, activities, _ := poll(t, p)
+	assert.Equal(t, []string{"1/1", "1/2"}, activities)
+
+	// Verify that an edited activity is emitted again, but only once
+	f.setActivity("1", "1", now.Add(-10*time.Second))
+	_, activities, _ = poll(t, p)
+	assert.Equal(t, []string{"1/1"}, activities)
+	_, activities, _ = poll(t, p)
+	assert.Empty(t, activities)
+}
+
+func Test_Poller_ClockSkew(t *testing.T) {
+	f, c := newFakeProgram(t)
+	p := NewPoller(c, h1.ReportListFilter{}, &Options{Interval: time.Minute, BufferSize: 10})
+	now := time.Now().UTC()
+
+	// Verify that activities from a server clock which is ahead of ours are emitted once
+	f.setActivity("1", "1", now.Add(5*time.Minute))
+	_, activities, _ := poll(t, p)
+	assert.Equal(t, []string{"1/1"}, activities)
+	_, activities, _ = poll(t, p)
+	assert.Empty(t, activities)
+
+	// Verify that activities from a server clock which is behind ours are emitted while within the window
+	f.setActivity("2", "2", now.Add(-90*time.Second))
+	_, activities, _ = poll(t, p)
+	assert.Equal(t, []string{"2/2"}, activities)
+	_, activities, _ = poll(t, p)
+	assert.Empty(t, activities)
+
+	// Verify that activities which are older than the window are ignored
+	f.setActivity("3", "3", now.Add(-time.Hour))
+	_, activities, _ = poll(t, p)
+	assert.Empty(t, activities)
+}
+
+func Test_Poller_Errors(t *testing.T) {
+	f, c := newFakeProgram(t)
+	p := NewPoller(c, h1.ReportListFilter{}, &Options{Interval: time.Minute, ContinueOnError: true})
+	f.setActivity("1", "1", time.Now().UTC())
+	f.failGet = true
+
+	// Verify that errors are dropped instead of blocking when nobody is listening
+	_, _, errs := poll(t, p)
+	assert.Empty(t, errs)
+
+	// Verify that a report which failed to be fetched is fetched again on the next poll
+	p = NewPoller(c, h1.ReportListFilter{}, &Options{Interval: time.Minute, BufferSize: 10})
+	_, _, errs = poll(t, p)
+	assert.Len(t, errs, 1)
+	f.failGet = false
+	_, activities, errs := poll(t, p)
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"1/1"}, activities)
+}
+
+func Test_Poller_Run(t *testing.T) {
+	f, c := newFakeProgram(t)
+	f.setActivity("1", "1", time.Now().UTC())
+	store := NewMemoryCheckpointStore()
+	p := NewPoller(c, h1.ReportListFilter{}, &Options{Interval: 10 * time.Millisecond, CheckpointStore: store})
+
+	// Verify that Run emits events until it is cancelled, then closes the channels
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- p.Run(ctx) }()
+	report, ok := <-p.Reports()
+	require.True(t, ok)
+	assert.Equal(t, "1", *report.ID)
+	activity, ok := <-p.Activities()
+	require.True(t, ok)
+	assert.Equal(t, "1", *activity.ID)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	_, ok = <-p.Activities()
+	assert.False(t, ok)
+	assert.Equal(t, ErrAlreadyRunning, p.Run(context.Background()))
+
+	// Verify that a restarted poller resumes from the checkpoint
+	p = NewPoller(c, h1.ReportListFilter{}, &Options{Interval: 10 * time.Millisecond, BufferSize: 10, CheckpointStore: store})
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, p.Run(ctx))
+	_, ok = <-p.Activities()
+	assert.False(t, ok)
+
+	// Verify that the events of the report being processed when Run is cancelled are delivered within the drain
+	// timeout, after which nothing else is emitted
+	f, c = newFakeProgram(t)
+	f.setActivity("2", "2", time.Now().UTC())
+	store = NewMemoryCheckpointStore()
+	p = NewPoller(c, h1.ReportListFilter{}, &Options{Interval: 10 * time.Millisecond, Window: time.Hour, DrainTimeout: time.Minute, CheckpointStore: store})
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() { done <- p.Run(ctx) }()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+		t.Fatal("Run returned before the pending events were received")
+	case <-time.After(20 * time.Millisecond):
+	}
+	report, ok = <-p.Reports()
+	require.True(t, ok)
+	assert.Equal(t, "2", *report.ID)
+	activity, ok = <-p.Activities()
+	require.True(t, ok)
+	assert.Equal(t, "2", *activity.ID)
+	assert.Equal(t, context.Canceled, <-done)
+	_, ok = <-p.Activities()
+	assert.False(t, ok)
+
+	// Verify that the delivered events are not emitted again after a restart
+	p = NewPoller(c, h1.ReportListFilter{}, &Options{Interval: 10 * time.Millisecond, Window: time.Hour, BufferSize: 10, CheckpointStore: store})
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, p.Run(ctx))
+	for report := range p.Reports() {
+		assert.Fail(t, "unexpected report", *report.ID)
+	}
+	for activity := range p.Activities() {
+		assert.Fail(t, "unexpected activity", *activity.ID)
+	}
+}
+
+func Test_Poller_BufferedDelivery(t *testing.T) {
+	f, c := newFakeProgram(t)
+	now := time.Now().UTC()
+	f.setActivity("1", "1", now)
+	f.setActivity("1", "2", now)
+	store := NewMemoryCheckpointStore()
+	// run polls for a while, stops and receives the events until the channels are closed. Unless drainTimeout is set,
+	// the events are only received once Run returned.
+	run := func(drainTimeout time.Duration) (events []string) {
+		p := NewPoller(c, h1.ReportListFilter{}, &Options{Interval: 10 * time.Millisecond, Window: time.Hour, BufferSize: 10, DrainTimeout: drainTimeout, CheckpointStore: store})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		done := make(chan error, 1)
+		go func() { done <- p.Run(ctx) }()
+		var err error
+		if drainTimeout == 0 {
+			err = <-done
+		}
+		reports, activities := p.Reports(), p.Activities()
+		for reports != nil || activities != nil {
+			select {
+			case report, ok := <-reports:
+				if !ok {
+					reports = nil
+					continue
+				}
+				events = append(events, *report.ID)
+			case activity, ok := <-activities:
+				if !ok {
+					activities = nil
+					continue
+				}
+				events = append(events, *activity.Report().ID+"/"+*activity.ID)
+			}
+		}
+		if drainTimeout > 0 {
+			err = <-done
+		}
+		assert.Equal(t, context.DeadlineExceeded, err)
+		sort.Strings(events)
+		return events
+	}
+
+	// Verify that buffered events which were not received before Run returned are taken back and not persisted
+	assert.Empty(t, run(0))
+	checkpoint, err := store.Load(context.Background())
+	require.Nil(t, err)
+	assert.Nil(t, checkpoint)
+
+	// Verify that they are emitted again after a restart, and persisted once they were received while draining
+	assert.Equal(t, []string{"1", "1/1", "1/2"}, run(time.Minute))
+	checkpoint, err = store.Load(context.Background())
+	require.Nil(t, err)
+	require.NotNil(t, checkpoint)
+	assert.Equal(t, []string{"1", "2"}, checkpoint.Reports["1"].ActivityIDs)
+
+	// Verify that nothing is emitted again once the events were persisted
+	assert.Empty(t, run(0))
+}
+
+func Test_Poller_ExactlyOnce(t *testing.T) {
+	now := time.Now().UTC()
+	// start runs a poller until it emitted all events of report 1, of which only the given ones are received, and
+	// then stops it
+	start := func(c *h1.Client, store CheckpointStore, reports, activities int) {
+		p := NewPoller(c, h1.ReportListFilter{}, &Options{Interval: time.Minute, Window: time.Hour, BufferSize: 10, CheckpointStore: store})
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- p.Run(ctx) }()
+		require.Eventually(t, func() bool { return len(p.reportChan)+len(p.activityChan) == 4 }, time.Second, time.Millisecond)
+		for idx := 0; idx < reports; idx++ {
+			<-p.Reports()
+		}
+		for idx := 0; idx < activities; idx++ {
+			<-p.Activities()
+		}
+		cancel()
+		assert.Equal(t, context.Canceled, <-done)
+
+		// Verify that the events which were not received were taken back
+		_, ok := <-p.Reports()
+		assert.False(t, ok)
+		_, ok = <-p.Activities()
+		assert.False(t, ok)
+	}
+	// resume polls once with a restarted poller and returns the emitted events
+	resume := func(c *h1.Client, store CheckpointStore) []string {
+		p := NewPoller(c, h1.ReportListFilter{}, &Options{Interval: time.Minute, Window: time.Hour, BufferSize: 10, CheckpointStore: store})
+		checkpoint, err := store.Load(context.Background())
+		require.Nil(t, err)
+		p.checkpoint = checkpoint
+		p.resumed = true
+		reports, activities, errs := poll(t, p)
+		assert.Empty(t, errs)
+		return append(reports, activities...)
+	}
+
+	// program returns a client for a new report with three activities
+	program := func() *h1.Client {
+		f, c := newFakeProgram(t)
+		f.setActivity("1", "1", now.Add(-3*time.Second))
+		f.setActivity("1", "2", now.Add(-2*time.Second))
+		f.setActivity("1", "3", now.Add(-1*time.Second))
+		return c
+	}
+
+	// Verify that only the events which were not received are emitted after a restart
+	c := program()
+	store := NewMemoryCheckpointStore()
+	start(c, store, 1, 1)
+	assert.Equal(t, []string{"1/2", "1/3"}, resume(c, store))
+
+	// Verify that a report which was not received is emitted again without the activities which were
+	c = program()
+	store = NewMemoryCheckpointStore()
+	start(c, store, 0, 2)
+	checkpoint, err := store.Load(context.Background())
+	require.Nil(t, err)
+	assert.True(t, checkpoint.Reports["1"].ReportPending)
+	assert.Equal(t, []string{"1", "1/3"}, resume(c, store))
+}
+
+func Test_Poller_NewestFirst(t *testing.T) {
+	for name, deduper := range map[string]Deduper{
+		"ttl":    NewTTLDeduper(100, time.Hour),
+		"id set": NewIDSetDeduper(),
+		"cursor": NewCursorDeduper(),
+	} {
+		f, c := newFakeProgram(t)
+		f.newestFirst = true
+		store := NewMemoryCheckpointStore()
+		p := NewPoller(c, h1.ReportListFilter{}, &Options{Interval: time.Minute, BufferSize: 10, CheckpointStore: store, Deduper: deduper})
+		now := time.Now().UTC()
+		f.setActivity("1", "1", now.Add(-30*time.Second))
+		f.setActivity("1", "2", now.Add(-20*time.Second))
+		f.setActivity("1", "3", now.Add(-10*time.Second))
+
+		// Verify that activities which the API returns newest first are all emitted, oldest first
+		_, activities, errs := poll(t, p)
+		assert.Empty(t, errs, name)
+		assert.Equal(t, []string{"1/1", "1/2", "1/3"}, activities, name)
+		p.settle(context.Background())
+		_, activities, _ = poll(t, p)
+		assert.Empty(t, activities, name)
+
+		// Verify that a restarted poller does not emit them again
+		checkpoint, err := store.Load(context.Background())
+		require.Nil(t, err, name)
+		p = NewPoller(c, h1.ReportListFilter{}, &Options{Interval: time.Minute, BufferSize: 10, CheckpointStore: store})
+		p.checkpoint = checkpoint
+		_, activities, _ = poll(t, p)
+		assert.Empty(t, activities, name)
+	}
+}
@@ -0,0 +1,429 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package polling
+
+import (
+	"github.com/uber-go/hackeroni/h1"
+
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// EventKind represent possible kinds of an Event
+const (
+	EventReport   = "report"
+	EventActivity = "activity"
+)
+
+// Event is a new report or activity emitted by a Poller or Scheduler
+type Event struct {
+	// One of the EventKind values
+	Kind string
+
+	// The new report, or the report the activity belongs to
+	Report *h1.Report
+
+	// The new activity, nil for report events
+	Activity *h1.Activity
+
+	// State of the report when the router saw it before, empty if it did not
+	PreviousState string
+
+	// Program and credential the event was polled for by a Scheduler, empty for events of a Poller
+	Tag Tag
+}
+
+// Handler handles an event. Handlers are called concurrently from the workers of a Router.
+type Handler func(ctx context.Context, event Event) error
+
+// StateTransition matches a change of the state of a report, such as h1.ReportStateTriaged.
+// An empty From or To matches any state.
+type StateTransition struct {
+	From string
+	To   string
+}
+
+// Rule selects the events a handler is called for. Every field which is set has to match.
+type Rule struct {
+	// Match only events of this EventKind
+	Kind string
+
+	// Match only activities of these types, such as h1.ActivityCommentType
+	ActivityTypes []string
+
+	// Match only events of reports of these program handles, or of the programs they were polled for by a Scheduler
+	Programs []string
+
+	// Match only internal activities if true, or only public ones if false
+	Internal *bool
+
+	// Match only events of reports whose state changed as described since the router saw them before
+	StateTransition *StateTransition
+}
+
+// matches reports whether the event is selected by the rule
+func (r *Rule) matches(event *Event) bool {
+	if r.Kind != "" && r.Kind != event.Kind {
+		return false
+	}
+	if len(r.ActivityTypes) > 0 && (event.Activity == nil || !contains(r.ActivityTypes, stringValue(event.Activity.Type))) {
+		return false
+	}
+	if len(r.Programs) > 0 && !contains(r.Programs, programHandle(event)) {
+		return false
+	}
+	if r.Internal != nil && (event.Activity == nil || event.Activity.Internal == nil || *event.Activity.Internal != *r.Internal) {
+		return false
+	}
+	if r.StateTransition != nil {
+		state := ""
+		if event.Report != nil {
+			state = stringValue(event.Report.State)
+		}
+		if event.PreviousState == "" || state == event.PreviousState {
+			return false
+		}
+		if (r.StateTransition.From != "" && r.StateTransition.From != event.PreviousState) ||
+			(r.StateTransition.To != "" && r.StateTransition.To != state) {
+			return false
+		}
+	}
+	return true
+}
+
+// programHandle returns the handle of the program of the event's report, falling back to the one of its Tag
+func programHandle(event *Event) string {
+	if event.Report != nil && event.Report.Program != nil && event.Report.Program.Handle != nil {
+		return *event.Report.Program.Handle
+	}
+	return event.Tag.Program
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+// HandlerError is reported to RouterOptions.OnError when a handler fails or panics
+type HandlerError struct {
+	Handler string
+	Event   Event
+	Err     error
+}
+
+func (e *HandlerError) Error() string {
+	return fmt.Sprintf("polling: handler %s failed: %v", e.Handler, e.Err)
+}
+
+// Unwrap returns the error of the handler
+func (e *HandlerError) Unwrap() error {
+	return e.Err
+}
+
+// RouterOptions configures a Router
+type RouterOptions struct {
+	// Number of calls of each handler which run at the same time. Defaults to 1.
+	Workers int
+
+	// Number of calls of each handler which are queued before dispatching blocks. Defaults to Workers.
+	QueueSize int
+
+	// How long a handler may run before its context is cancelled. Defaults to one minute, a negative value disables
+	// the limit.
+	HandlerTimeout time.Duration
+
+	// How long the state of a report is remembered after its last event to detect state transitions.
+	// Defaults to 30 days.
+	StateTTL time.Duration
+
+	// Called with errors of the poller and *HandlerError values of failed handlers. It is called from several
+	// goroutines at once. Errors are dropped if it is nil.
+	OnError func(err error)
+}
+
+// route is a handler registered with a Router
+type route struct {
+	name    string
+	rule    Rule
+	handler Handler
+}
+
+// job is a single call of a handler
+type job struct {
+	route *route
+	event Event
+}
+
+// reportState is the state of a report when the router last saw it
+type reportState struct {
+	state  string
+	seenAt time.Time
+}
+
+// Router dispatches the events of a Poller to the handlers whose rule matches them. Every handler has its own queue and
+// bounded pool of workers, so a failing, panicking or slow handler does not affect the others. Handlers of the same
+// event run concurrently and events are not guaranteed to be handled in order.
+//
+// Dispatching blocks while the queue of a handler is full, so a handler which hangs on all of its workers eventually
+// stalls the other handlers and, by no longer receiving its events, the poller. Handlers should return once their
+// context is done, which happens after RouterOptions.HandlerTimeout.
+type Router struct {
+	mu             sync.Mutex
+	routes         []*route
+	workers        int
+	queue          int
+	handlerTimeout time.Duration
+	onError        func(err error)
+	stateTTL       time.Duration
+	states         map[string]reportState // Last seen state per report ID
+	pruneAt        time.Time              // When states are next checked for expired ones
+	now            func() time.Time
+}
+
+// NewRouter returns a Router without handlers. If nil opts are provided, the defaults are used.
+func NewRouter(opts *RouterOptions) *Router {
+	var o RouterOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Workers <= 0 {
+		o.Workers = 1
+	}
+	if o.QueueSize <= 0 {
+		o.QueueSize = o.Workers
+	}
+	if o.HandlerTimeout == 0 {
+		o.HandlerTimeout = time.Minute
+	}
+	if o.StateTTL <= 0 {
+		o.StateTTL = 30 * 24 * time.Hour
+	}
+	return &Router{
+		workers:        o.Workers,
+		queue:          o.QueueSize,
+		handlerTimeout: o.HandlerTimeout,
+		onError:        o.OnError,
+		stateTTL:       o.StateTTL,
+		states:         make(map[string]reportState),
+		now:            time.Now,
+	}
+}
+
+// Handle registers a handler for the events matching rule. The name identifies the handler in a HandlerError.
+func (r *Router) Handle(name string, rule Rule, handler Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = append(r.routes, &route{name: name, rule: rule, handler: handler})
+}
+
+// Run dispatches the events of the poller until its channels are closed, which happens once the poller's Run returns,
+// and waits for the running handlers to finish. Handlers are called with ctx. Once ctx is done, handler calls which
+// can't be queued right away are dropped and reported to RouterOptions.OnError.
+func (r *Router) Run(ctx context.Context, p *Poller) {
+	errors, reports, activities := p.Errors(), p.Reports(), p.Activities()
+	r.run(ctx, func() (Event, bool, error) {
+		for errors != nil || reports != nil || activities != nil {
+			select {
+			case err, ok := <-errors:
+				if !ok {
+					errors = nil
+					continue
+				}
+				return Event{}, true, err
+			case report, ok := <-reports:
+				if !ok {
+					reports = nil
+					continue
+				}
+				return Event{Kind: EventReport, Report: report}, true, nil
+			case activity, ok := <-activities:
+				if !ok {
+					activities = nil
+					continue
+				}
+				return Event{Kind: EventActivity, Report: activity.Report(), Activity: &activity}, true, nil
+			}
+		}
+		return Event{}, false, nil
+	})
+}
+
+// RunScheduler dispatches the events of the scheduler like Run does for a Poller, until the scheduler's Run returns.
+// Events carry the Tag of the program they were polled for, and errors are passed on as *TaggedError values.
+func (r *Router) RunScheduler(ctx context.Context, s *Scheduler) {
+	errors, reports, activities := s.Errors(), s.Reports(), s.Activities()
+	r.run(ctx, func() (Event, bool, error) {
+		for errors != nil || reports != nil || activities != nil {
+			select {
+			case err, ok := <-errors:
+				if !ok {
+					errors = nil
+					continue
+				}
+				return Event{}, true, err
+			case report, ok := <-reports:
+				if !ok {
+					reports = nil
+					continue
+				}
+				return Event{Kind: EventReport, Report: report.Report, Tag: report.Tag}, true, nil
+			case activity, ok := <-activities:
+				if !ok {
+					activities = nil
+					continue
+				}
+				return Event{Kind: EventActivity, Report: activity.Activity.Report(), Activity: &activity.Activity, Tag: activity.Tag}, true, nil
+			}
+		}
+		return Event{}, false, nil
+	})
+}
+
+// run dispatches the events returned by next until it reports that there are none left, and waits for the running
+// handlers to finish
+func (r *Router) run(ctx context.Context, next func() (Event, bool, error)) {
+	// Every route gets its queue and workers once it matches an event, so routes can be added while running
+	queues := make(map[*route]chan job)
+	var wg sync.WaitGroup
+	queue := func(route *route) chan<- job {
+		jobs, ok := queues[route]
+		if ok {
+			return jobs
+		}
+		jobs = make(chan job, r.queue)
+		queues[route] = jobs
+		for i := 0; i < r.workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range jobs {
+					r.call(ctx, j)
+				}
+			}()
+		}
+		return jobs
+	}
+
+	for {
+		event, ok, err := next()
+		if !ok {
+			break
+		}
+		if err != nil {
+			r.reportError(err)
+			continue
+		}
+		r.dispatch(ctx, queue, event)
+	}
+
+	for _, jobs := range queues {
+		close(jobs)
+	}
+	wg.Wait()
+}
+
+// dispatch queues a call of every handler whose rule matches the event on the queue of the handler
+func (r *Router) dispatch(ctx context.Context, queue func(route *route) chan<- job, event Event) {
+	if event.Report != nil && event.Report.ID != nil {
+		now := r.now()
+		r.pruneStates(now)
+		if previous, ok := r.states[*event.Report.ID]; ok && now.Sub(previous.seenAt) <= r.stateTTL {
+			event.PreviousState = previous.state
+		}
+		if event.Report.State != nil {
+			r.states[*event.Report.ID] = reportState{state: *event.Report.State, seenAt: now}
+		}
+	}
+
+	r.mu.Lock()
+	routes := r.routes
+	r.mu.Unlock()
+	for _, route := range routes {
+		if !route.rule.matches(&event) {
+			continue
+		}
+		j := job{route: route, event: event}
+		jobs := queue(route)
+		select {
+		case jobs <- j:
+			continue
+		default:
+		}
+		select {
+		case jobs <- j:
+		case <-ctx.Done():
+			r.reportError(&HandlerError{Handler: route.name, Event: event, Err: ctx.Err()})
+		}
+	}
+}
+
+// pruneStates forgets the states which expired, at most once per StateTTL so that it stays cheap
+func (r *Router) pruneStates(now time.Time) {
+	if now.Before(r.pruneAt) {
+		return
+	}
+	for id, state := range r.states {
+		if now.Sub(state.seenAt) > r.stateTTL {
+			delete(r.states, id)
+		}
+	}
+	r.pruneAt = now.Add(r.stateTTL)
+}
+
+// call runs a handler, turning its error or panic into a HandlerError
+func (r *Router) call(ctx context.Context, j job) {
+	var err error
+	func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				err = fmt.Errorf("panic: %v", recovered)
+			}
+		}()
+		if r.handlerTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, r.handlerTimeout)
+			defer cancel()
+		}
+		err = j.route.handler(ctx, j.event)
+	}()
+	if err != nil {
+		r.reportError(&HandlerError{Handler: j.route.name, Event: j.event, Err: err})
+	}
+}
+
+func (r *Router) reportError(err error) {
+	if r.onError != nil {
+		r.onError(err)
+	}
+}
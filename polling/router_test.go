@@ -0,0 +1,361 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package polling
+
+import (
+	"github.com/uber-go/hackeroni/h1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testReport(id, program, state string) *h1.Report {
+	return &h1.Report{ID: h1.String(id), State: h1.String(state), Program: &h1.Program{Handle: h1.String(program)}}
+}
+
+func Test_Rule_Matches(t *testing.T) {
+	report := testReport("1", "security", h1.ReportStateTriaged)
+	comment := &h1.Activity{Type: h1.String(h1.ActivityCommentType), Internal: h1.Bool(true)}
+	reportEvent := Event{Kind: EventReport, Report: report}
+	activityEvent := Event{Kind: EventActivity, Report: report, Activity: comment}
+	transitionEvent := Event{Kind: EventActivity, Report: report, Activity: comment, PreviousState: h1.ReportStateNew}
+
+	for _, tc := range []struct {
+		rule    Rule
+		event   Event
+		matches bool
+	}{
+		{Rule{}, reportEvent, true},
+		{Rule{Kind: EventReport}, reportEvent, true},
+		{Rule{Kind: EventReport}, activityEvent, false},
+		{Rule{ActivityTypes: []string{h1.ActivityCommentType}}, activityEvent, true},
+		{Rule{ActivityTypes: []string{h1.ActivityBugTriagedType}}, activityEvent, false},
+		{Rule{ActivityTypes: []string{h1.ActivityCommentType}}, reportEvent, false},
+		{Rule{Programs: []string{"security"}}, reportEvent, true},
+		{Rule{Programs: []string{"other"}}, reportEvent, false},
+		{Rule{Internal: h1.Bool(true)}, activityEvent, true},
+		{Rule{Internal: h1.Bool(false)}, activityEvent, false},
+		{Rule{Internal: h1.Bool(false)}, reportEvent, false},
+		{Rule{StateTransition: &StateTransition{}}, activityEvent, false},
+		{Rule{StateTransition: &StateTransition{}}, transitionEvent, true},
+		{Rule{StateTransition: &StateTransition{From: h1.ReportStateNew, To: h1.ReportStateTriaged}}, transitionEvent, true},
+		{Rule{StateTransition: &StateTransition{To: h1.ReportStateResolved}}, transitionEvent, false},
+		{Rule{StateTransition: &StateTransition{From: h1.ReportStateTriaged}}, transitionEvent, false},
+	} {
+		assert.Equal(t, tc.matches, tc.rule.matches(&tc.event), "%+v", tc.rule)
+	}
+}
+
+// feedPoller returns a Poller which emits the given events without polling
+func feedPoller(err error, reports []*h1.Report, activities []h1.Activity) *Poller {
+	p := NewPoller(nil, h1.ReportListFilter{}, &Options{BufferSize: len(reports) + len(activities) + 1})
+	if err != nil {
+		p.errorChan <- err
+	}
+	for _, report := range reports {
+		p.reportChan <- report
+	}
+	for _, activity := range activities {
+		p.activityChan <- activity
+	}
+	close(p.errorChan)
+	close(p.reportChan)
+	close(p.activityChan)
+	return p
+}
+
+// testActivities returns the activities of a report decoded like the API would
+func testActivities(t *testing.T, id, program, state string, activityTypes ...string) []h1.Activity {
+	var activities []string
+	for idx, activityType := range activityTypes {
+		activities = append(activities, fmt.Sprintf(`{"id":"%d","type":%q,"attributes":{"internal":false}}`, idx, activityType))
+	}
+	var report h1.Report
+	require.NoError(t, json.Unmarshal([]byte(fmt.Sprintf(
+		`{"id":%q,"type":"report","attributes":{"state":%q},"relationships":{"program":{"data":{"id":"1","type":"program","attributes":{"handle":%q}}},"activities":{"data":[%s]}}}`,
+		id, state, program, strings.Join(activities, ","),
+	)), &report))
+	return report.Activities
+}
+
+func Test_Router_Run(t *testing.T) {
+	var mu sync.Mutex
+	var errs []error
+	var calls []string
+	record := func(name string) Handler {
+		return func(ctx context.Context, event Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, name+":"+event.Kind+":"+*event.Report.ID)
+			return nil
+		}
+	}
+	router := NewRouter(&RouterOptions{Workers: 3, OnError: func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}})
+	router.Handle("slack", Rule{Kind: EventReport, Programs: []string{"security"}}, record("slack"))
+	router.Handle("oncall", Rule{StateTransition: &StateTransition{From: h1.ReportStateNew, To: h1.ReportStateTriaged}}, record("oncall"))
+	router.Handle("metrics", Rule{ActivityTypes: []string{h1.ActivityBugTriagedType}}, record("metrics"))
+
+	// Verify that poller errors are passed on and only matching handlers are called
+	router.Run(context.Background(), feedPoller(errors.New("poll failed"), []*h1.Report{
+		testReport("1", "security", h1.ReportStateNew),
+		testReport("2", "other", h1.ReportStateNew),
+	}, nil))
+	assert.Equal(t, []string{"slack:report:1"}, calls)
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "poll failed")
+
+	// Verify that the transition is only reported for the first event after it
+	calls = nil
+	router.Run(context.Background(), feedPoller(nil, nil, testActivities(t, "1", "security", h1.ReportStateTriaged,
+		h1.ActivityBugTriagedType, h1.ActivityCommentType,
+	)))
+	sort.Strings(calls)
+	assert.Equal(t, []string{"metrics:activity:1", "oncall:activity:1"}, calls)
+}
+
+func Test_Router_HandlerErrors(t *testing.T) {
+	var mu sync.Mutex
+	var errs []*HandlerError
+	router := NewRouter(&RouterOptions{Workers: 2, OnError: func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		var handlerErr *HandlerError
+		if errors.As(err, &handlerErr) {
+			errs = append(errs, handlerErr)
+		}
+	}})
+	var called int32
+	router.Handle("failing", Rule{}, func(ctx context.Context, event Event) error {
+		return errors.New("oh no")
+	})
+	router.Handle("panicking", Rule{}, func(ctx context.Context, event Event) error {
+		panic("oh no")
+	})
+	router.Handle("working", Rule{}, func(ctx context.Context, event Event) error {
+		atomic.AddInt32(&called, 1)
+		return nil
+	})
+
+	// Verify that failing and panicking handlers do not prevent the others from running
+	router.Run(context.Background(), feedPoller(nil, []*h1.Report{testReport("1", "security", h1.ReportStateNew), testReport("2", "security", h1.ReportStateNew)}, nil))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&called))
+	assert.Len(t, errs, 4)
+	names := map[string]int{}
+	for _, err := range errs {
+		names[err.Handler]++
+	}
+	assert.Equal(t, map[string]int{"failing": 2, "panicking": 2}, names)
+}
+
+func Test_Router_Bounded(t *testing.T) {
+	var running, maxRunning int32
+	router := NewRouter(&RouterOptions{Workers: 2})
+	router.Handle("slow", Rule{}, func(ctx context.Context, event Event) error {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	})
+
+	// Verify that no more handlers than workers run at once, and that Run waits for them
+	var reports []*h1.Report
+	for i := 0; i < 10; i++ {
+		reports = append(reports, testReport("1", "security", h1.ReportStateNew))
+	}
+	router.Run(context.Background(), feedPoller(nil, reports, nil))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxRunning))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&running))
+}
+
+func Test_Router_States(t *testing.T) {
+	var mu sync.Mutex
+	var transitions []string
+	now := time.Date(2016, 2, 2, 4, 5, 6, 0, time.UTC)
+	router := NewRouter(&RouterOptions{StateTTL: time.Hour})
+	router.now = func() time.Time { return now }
+	router.Handle("oncall", Rule{StateTransition: &StateTransition{}}, func(ctx context.Context, event Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		transitions = append(transitions, event.PreviousState+">"+*event.Report.State)
+		return nil
+	})
+	run := func(reports ...*h1.Report) {
+		router.Run(context.Background(), feedPoller(nil, reports, nil))
+	}
+
+	// Verify that transitions are detected while the previous state is remembered
+	run(testReport("1", "security", h1.ReportStateNew), testReport("2", "security", h1.ReportStateNew))
+	now = now.Add(30 * time.Minute)
+	run(testReport("1", "security", h1.ReportStateTriaged))
+	assert.Equal(t, []string{h1.ReportStateNew + ">" + h1.ReportStateTriaged}, transitions)
+
+	// Verify that states expire and are pruned once no event was seen for StateTTL
+	now = now.Add(45 * time.Minute)
+	run(testReport("2", "security", h1.ReportStateTriaged))
+	assert.Len(t, transitions, 1)
+	now = now.Add(2 * time.Hour)
+	run(testReport("3", "security", h1.ReportStateNew))
+	assert.Equal(t, []string{"3"}, func() []string {
+		var ids []string
+		for id := range router.states {
+			ids = append(ids, id)
+		}
+		return ids
+	}())
+}
+
+func Test_Router_HangingHandler(t *testing.T) {
+	var mu sync.Mutex
+	var errs []error
+	router := NewRouter(&RouterOptions{Workers: 1, HandlerTimeout: 10 * time.Millisecond, OnError: func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}})
+	var called int32
+	router.Handle("hanging", Rule{Programs: []string{"hanging"}}, func(ctx context.Context, event Event) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	router.Handle("working", Rule{Programs: []string{"security"}}, func(ctx context.Context, event Event) error {
+		atomic.AddInt32(&called, 1)
+		return nil
+	})
+
+	// Verify that a handler which blocks until its context is done is stopped by the timeout
+	router.Run(context.Background(), feedPoller(nil, []*h1.Report{
+		testReport("1", "hanging", h1.ReportStateNew),
+		testReport("2", "hanging", h1.ReportStateNew),
+		testReport("3", "security", h1.ReportStateNew),
+	}, nil))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&called))
+	require.Len(t, errs, 2)
+	assert.True(t, errors.Is(errs[0], context.DeadlineExceeded))
+
+	// Verify that a blocked handler does not delay the calls of another handler
+	errs = nil
+	release := make(chan struct{})
+	called = 0
+	router = NewRouter(&RouterOptions{Workers: 1})
+	assert.Equal(t, time.Minute, router.handlerTimeout)
+	router.Handle("blocking", Rule{}, func(ctx context.Context, event Event) error {
+		<-release
+		return nil
+	})
+	router.Handle("working", Rule{}, func(ctx context.Context, event Event) error {
+		atomic.AddInt32(&called, 1)
+		return nil
+	})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		router.Run(context.Background(), feedPoller(nil, []*h1.Report{
+			testReport("1", "security", h1.ReportStateNew),
+			testReport("2", "security", h1.ReportStateNew),
+		}, nil))
+	}()
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&called) == 2 }, time.Second, time.Millisecond)
+	close(release)
+	<-done
+
+	// Verify that calls which can't be queued are dropped once ctx is done, instead of waiting for a blocked handler
+	errs = nil
+	release = make(chan struct{})
+	var once sync.Once
+	router = NewRouter(&RouterOptions{Workers: 1, OnError: func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+		once.Do(func() { close(release) })
+	}})
+	router.Handle("blocking", Rule{}, func(ctx context.Context, event Event) error {
+		<-release
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var reports []*h1.Report
+	for i := 0; i < 4; i++ {
+		reports = append(reports, testReport(fmt.Sprint(i), "security", h1.ReportStateNew))
+	}
+	router.Run(ctx, feedPoller(nil, reports, nil))
+	require.NotEmpty(t, errs)
+	assert.True(t, errors.Is(errs[0], context.Canceled))
+}
+
+func Test_Router_RunScheduler(t *testing.T) {
+	f, c := newFakeProgram(t)
+	c.RateLimiter = &h1.RateLimiter{}
+	f.setActivity("1", "1", time.Now().UTC())
+	s := NewScheduler(nil)
+	require.NoError(t, s.AddCredential("triage", c))
+	require.NoError(t, s.AddProgram("triage", "security", h1.ReportListFilter{}, &Options{Interval: time.Minute}))
+
+	var mu sync.Mutex
+	var calls []string
+	var errs []error
+	router := NewRouter(&RouterOptions{OnError: func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err)
+	}})
+	router.Handle("security", Rule{Programs: []string{"security"}}, func(ctx context.Context, event Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, event.Kind+":"+event.Tag.Program+":"+event.Tag.Credential)
+		return nil
+	})
+	router.Handle("other", Rule{Programs: []string{"other"}}, func(ctx context.Context, event Event) error {
+		return errors.New("unexpected event")
+	})
+
+	// Verify that the events of the scheduler are dispatched with their tag until it stops
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+	router.RunScheduler(context.Background(), s)
+	assert.Equal(t, context.DeadlineExceeded, <-done)
+	sort.Strings(calls)
+	assert.Equal(t, []string{"activity:security:triage", "report:security:triage"}, calls)
+	assert.Empty(t, errs)
+}
@@ -0,0 +1,364 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package polling
+
+import (
+	"github.com/uber-go/hackeroni/h1"
+
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrSchedulerStopped is returned when programs are added to a Scheduler whose Run returned
+	ErrSchedulerStopped = errors.New("polling: scheduler stopped")
+
+	// ErrUnknownCredential is returned when a program is added with a credential which was not added before
+	ErrUnknownCredential = errors.New("polling: unknown credential")
+
+	// ErrUnknownProgram is returned when a program which is not polled is removed
+	ErrUnknownProgram = errors.New("polling: unknown program")
+
+	// ErrDuplicate is returned when a credential or program is added twice
+	ErrDuplicate = errors.New("polling: already added")
+
+	// ErrNoRateLimiter is returned when a credential is added with a client which has no h1.RateLimiter
+	ErrNoRateLimiter = errors.New("polling: client has no rate limiter")
+
+	// ErrSharedState is returned when a program is added with the CheckpointStore or Deduper of another program
+	ErrSharedState = errors.New("polling: checkpoint store or deduper used by another program")
+)
+
+// Tag identifies the program and credential an event was polled for
+type Tag struct {
+	// Handle of the program
+	Program string
+
+	// Name of the credential
+	Credential string
+}
+
+// TaggedReport is a new report emitted by a Scheduler
+type TaggedReport struct {
+	Tag
+	Report *h1.Report
+}
+
+// TaggedActivity is a new activity emitted by a Scheduler
+type TaggedActivity struct {
+	Tag
+	Activity h1.Activity
+}
+
+// TaggedError is an error encountered while polling a program of a Scheduler
+type TaggedError struct {
+	Tag
+	Err error
+}
+
+func (e *TaggedError) Error() string {
+	return fmt.Sprintf("polling: program %s with credential %s: %v", e.Program, e.Credential, e.Err)
+}
+
+// Unwrap returns the error of the poller
+func (e *TaggedError) Unwrap() error {
+	return e.Err
+}
+
+// SchedulerOptions configures a Scheduler
+type SchedulerOptions struct {
+	// Delay between the first polls of programs sharing a credential, so that they do not hit the API at once.
+	// The offsets wrap around at the interval of each program. Defaults to five seconds.
+	Stagger time.Duration
+
+	// Number of events each channel buffers before a send blocks. Zero makes the channels unbuffered.
+	BufferSize int
+}
+
+// credential is an API identity added to a Scheduler
+type credential struct {
+	client *h1.Client
+	slots  int // Number of programs added so far, used to stagger them
+}
+
+// program is a program polled by a Scheduler
+type program struct {
+	tag             Tag
+	poller          *Poller
+	offset          time.Duration // Delay before the first poll
+	continueOnError bool          // Whether errors are dropped when they can't be sent right away
+	cancel          context.CancelFunc
+	started         bool
+}
+
+// Scheduler polls many programs with several credentials and emits their events, tagged with their origin, on a
+// single set of channels. Programs polled with the same credential share its client and therefore the budget of its
+// h1.RateLimiter. Programs can be added and removed while the scheduler is running. Router.RunScheduler dispatches
+// the events of a Scheduler to handlers.
+type Scheduler struct {
+	mu           sync.Mutex
+	stagger      time.Duration
+	credentials  map[string]*credential
+	programs     map[string]*program
+	ctx          context.Context // Context of Run, nil until it is called
+	stopped      bool
+	wg           sync.WaitGroup
+	errorChan    chan *TaggedError
+	reportChan   chan TaggedReport
+	activityChan chan TaggedActivity
+}
+
+// NewScheduler returns a Scheduler without credentials or programs. If nil opts are provided, the defaults are used.
+func NewScheduler(opts *SchedulerOptions) *Scheduler {
+	var o SchedulerOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Stagger <= 0 {
+		o.Stagger = 5 * time.Second
+	}
+	if o.BufferSize < 0 {
+		o.BufferSize = 0
+	}
+	return &Scheduler{
+		stagger:      o.Stagger,
+		credentials:  make(map[string]*credential),
+		programs:     make(map[string]*program),
+		errorChan:    make(chan *TaggedError, o.BufferSize),
+		reportChan:   make(chan TaggedReport, o.BufferSize),
+		activityChan: make(chan TaggedActivity, o.BufferSize),
+	}
+}
+
+// Errors returns the channel on which errors encountered while polling are emitted. It is closed when Run returns.
+func (s *Scheduler) Errors() <-chan *TaggedError {
+	return s.errorChan
+}
+
+// Reports returns the channel on which new reports are emitted. It is closed when Run returns.
+func (s *Scheduler) Reports() <-chan TaggedReport {
+	return s.reportChan
+}
+
+// Activities returns the channel on which new activities are emitted. It is closed when Run returns.
+func (s *Scheduler) Activities() <-chan TaggedActivity {
+	return s.activityChan
+}
+
+// AddCredential adds an API identity under the given name. The client must have a RateLimiter, which is the budget
+// shared by the programs of the credential. A zero value h1.RateLimiter does not limit requests itself, but still
+// pauses all of them once the API reports that the quota is exhausted.
+func (s *Scheduler) AddCredential(name string, client *h1.Client) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.credentials[name]; ok {
+		return fmt.Errorf("%w: credential %q", ErrDuplicate, name)
+	}
+	if client.RateLimiter == nil {
+		return fmt.Errorf("%w: credential %q", ErrNoRateLimiter, name)
+	}
+	s.credentials[name] = &credential{client: client}
+	return nil
+}
+
+// AddProgram polls the reports of the program with the given handle which match filter, using the named credential.
+// The Program of filter is replaced by the handle. If nil opts are provided, the defaults of NewPoller are used.
+// The same opts can be used for several programs, but every program needs its own CheckpointStore and Deduper, so
+// those are left unset to get a default one per program. If the scheduler is running, polling starts right away.
+func (s *Scheduler) AddProgram(credentialName, handle string, filter h1.ReportListFilter, opts *Options) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return ErrSchedulerStopped
+	}
+	cred, ok := s.credentials[credentialName]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownCredential, credentialName)
+	}
+	if _, ok := s.programs[handle]; ok {
+		return fmt.Errorf("%w: program %q", ErrDuplicate, handle)
+	}
+	if opts != nil {
+		for _, prog := range s.programs {
+			if sameState(opts.CheckpointStore, prog.poller.checkpointStore) || sameState(opts.Deduper, prog.poller.deduper) {
+				return fmt.Errorf("%w: programs %q and %q", ErrSharedState, prog.tag.Program, handle)
+			}
+		}
+	}
+
+	filter.Program = []string{handle}
+	poller := NewPoller(cred.client, filter, opts)
+	prog := &program{
+		tag:             Tag{Program: handle, Credential: credentialName},
+		poller:          poller,
+		offset:          (time.Duration(cred.slots) * s.stagger) % poller.interval,
+		continueOnError: poller.continueOnError,
+	}
+	cred.slots++
+	s.programs[handle] = prog
+	if s.ctx != nil {
+		s.start(prog)
+	}
+	return nil
+}
+
+// sameState reports whether a and b are the same CheckpointStore or Deduper. Values which can't be compared never are.
+func sameState(a, b interface{}) bool {
+	if a == nil || b == nil || !reflect.TypeOf(a).Comparable() || !reflect.TypeOf(b).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// RemoveProgram stops polling the program with the given handle. Events which were already polled may still be
+// emitted after it returns. The program can be added again right away.
+func (s *Scheduler) RemoveProgram(handle string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prog, ok := s.programs[handle]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownProgram, handle)
+	}
+	delete(s.programs, handle)
+	if prog.started {
+		prog.cancel()
+	}
+	return nil
+}
+
+// Programs returns the programs which are currently polled, sorted by handle
+func (s *Scheduler) Programs() []Tag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tags := make([]Tag, 0, len(s.programs))
+	for _, prog := range s.programs {
+		tags = append(tags, prog.tag)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Program < tags[j].Program })
+	return tags
+}
+
+// Run polls every program until ctx is done, waits for the pollers to stop and returns the error of ctx.
+// All channels are closed when it returns. Every report and activity emitted by a poller is delivered before, so
+// consumers have to keep receiving until the channels are closed for Run to return. A Scheduler can only be run once.
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	if s.ctx != nil || s.stopped {
+		s.mu.Unlock()
+		return ErrAlreadyRunning
+	}
+	s.ctx = ctx
+	for _, prog := range s.programs {
+		s.start(prog)
+	}
+	s.mu.Unlock()
+
+	<-ctx.Done()
+
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+	s.wg.Wait()
+	close(s.errorChan)
+	close(s.reportChan)
+	close(s.activityChan)
+	return ctx.Err()
+}
+
+// start runs the poller of a program after its offset. It must be called with s.mu held.
+func (s *Scheduler) start(prog *program) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	prog.cancel = cancel
+	prog.started = true
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+
+		timer := time.NewTimer(prog.offset)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			s.forward(prog)
+		}()
+		if err := prog.poller.Run(ctx); err != nil && ctx.Err() == nil {
+			s.sendError(prog, err)
+		}
+		<-done
+	}()
+}
+
+// forward emits the events of a program's poller on the channels of the scheduler until the poller stops. Reports
+// and activities are never dropped, since the poller persists its progress once they were received.
+func (s *Scheduler) forward(prog *program) {
+	p := prog.poller
+	errs, reports, activities := p.Errors(), p.Reports(), p.Activities()
+	for errs != nil || reports != nil || activities != nil {
+		select {
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			s.sendError(prog, err)
+		case report, ok := <-reports:
+			if !ok {
+				reports = nil
+				continue
+			}
+			s.reportChan <- TaggedReport{Tag: prog.tag, Report: report}
+		case activity, ok := <-activities:
+			if !ok {
+				activities = nil
+				continue
+			}
+			s.activityChan <- TaggedActivity{Tag: prog.tag, Activity: activity}
+		}
+	}
+}
+
+// sendError emits an error of a program, dropping it if it can't be sent right away and the program continues on errors
+func (s *Scheduler) sendError(prog *program, err error) {
+	tagged := &TaggedError{Tag: prog.tag, Err: err}
+	if prog.continueOnError {
+		select {
+		case s.errorChan <- tagged:
+		default:
+		}
+		return
+	}
+	select {
+	case s.errorChan <- tagged:
+	case <-s.ctx.Done():
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2016 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package polling
+
+import (
+	"github.com/uber-go/hackeroni/h1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func Test_Scheduler_Add(t *testing.T) {
+	_, c := newFakeProgram(t)
+	s := NewScheduler(&SchedulerOptions{Stagger: 10 * time.Second})
+
+	// Verify that credentials need a rate limiter, which is not assigned behind the caller's back
+	assert.True(t, errors.Is(s.AddCredential("triage", c), ErrNoRateLimiter))
+	assert.Nil(t, c.RateLimiter)
+
+	// Verify that mistakes are rejected
+	c.RateLimiter = &h1.RateLimiter{}
+	require.NoError(t, s.AddCredential("triage", c))
+	assert.True(t, errors.Is(s.AddCredential("triage", c), ErrDuplicate))
+	assert.True(t, errors.Is(s.AddProgram("unknown", "security", h1.ReportListFilter{}, nil), ErrUnknownCredential))
+	require.NoError(t, s.AddProgram("triage", "security", h1.ReportListFilter{}, &Options{Interval: 25 * time.Second}))
+	assert.True(t, errors.Is(s.AddProgram("triage", "security", h1.ReportListFilter{}, nil), ErrDuplicate))
+	assert.True(t, errors.Is(s.RemoveProgram("unknown"), ErrUnknownProgram))
+
+	// Verify that programs sharing a credential share its client and are staggered within their interval
+	for _, handle := range []string{"payments", "mobile", "web"} {
+		require.NoError(t, s.AddProgram("triage", handle, h1.ReportListFilter{State: []string{h1.ReportStateNew}}, &Options{Interval: 25 * time.Second}))
+	}
+	offsets := map[string]time.Duration{}
+	for handle, prog := range s.programs {
+		offsets[handle] = prog.offset
+		assert.Equal(t, c, prog.poller.client)
+		assert.Equal(t, []string{handle}, prog.poller.filter.Program)
+	}
+	assert.Equal(t, map[string]time.Duration{"security": 0, "payments": 10 * time.Second, "mobile": 20 * time.Second, "web": 5 * time.Second}, offsets)
+	assert.Equal(t, []string{h1.ReportStateNew}, s.programs["web"].poller.filter.State)
+
+	// Verify that programs can be removed before running
+	require.NoError(t, s.RemoveProgram("web"))
+	assert.Equal(t, []Tag{
+		{Program: "mobile", Credential: "triage"},
+		{Program: "payments", Credential: "triage"},
+		{Program: "security", Credential: "triage"},
+	}, s.Programs())
+
+	// Verify that programs can share options, but not their checkpoint store or deduper
+	s = NewScheduler(nil)
+	require.NoError(t, s.AddCredential("triage", c))
+	shared := &Options{Interval: 25 * time.Second, CheckpointStore: NewMemoryCheckpointStore()}
+	require.NoError(t, s.AddProgram("triage", "shared", h1.ReportListFilter{}, shared))
+	assert.True(t, errors.Is(s.AddProgram("triage", "other", h1.ReportListFilter{}, shared), ErrSharedState))
+	shared.CheckpointStore = nil
+	shared.Deduper = s.programs["shared"].poller.deduper
+	assert.True(t, errors.Is(s.AddProgram("triage", "other", h1.ReportListFilter{}, shared), ErrSharedState))
+	shared.Deduper = nil
+	require.NoError(t, s.AddProgram("triage", "other", h1.ReportListFilter{}, shared))
+	assert.NotSame(t, s.programs["shared"].poller.checkpointStore, s.programs["other"].poller.checkpointStore)
+}
+
+func Test_Scheduler_Run(t *testing.T) {
+	triage, triageClient := newFakeProgram(t)
+	triage.setActivity("1", "1", time.Now().UTC())
+	bounty, bountyClient := newFakeProgram(t)
+	bounty.setActivity("2", "2", time.Now().UTC())
+	bounty.failGet = true
+	opts := &Options{Interval: 10 * time.Millisecond}
+	triageClient.RateLimiter = &h1.RateLimiter{}
+	bountyClient.RateLimiter = &h1.RateLimiter{}
+
+	s := NewScheduler(&SchedulerOptions{Stagger: time.Millisecond})
+	require.NoError(t, s.AddCredential("triage", triageClient))
+	require.NoError(t, s.AddCredential("bounty", bountyClient))
+	require.NoError(t, s.AddProgram("triage", "security", h1.ReportListFilter{}, opts))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+
+	// Verify that events are tagged with their program and credential
+	report := <-s.Reports()
+	assert.Equal(t, Tag{Program: "security", Credential: "triage"}, report.Tag)
+	assert.Equal(t, "1", *report.Report.ID)
+	activity := <-s.Activities()
+	assert.Equal(t, Tag{Program: "security", Credential: "triage"}, activity.Tag)
+	assert.Equal(t, "1", *activity.Activity.ID)
+
+	// Verify that programs can be added and removed at runtime
+	require.NoError(t, s.AddProgram("bounty", "payments", h1.ReportListFilter{}, opts))
+	err := <-s.Errors()
+	assert.Equal(t, Tag{Program: "payments", Credential: "bounty"}, err.Tag)
+	var apiErr *h1.ErrorResponse
+	assert.True(t, errors.As(err, &apiErr))
+	require.NoError(t, s.RemoveProgram("payments"))
+	require.NoError(t, s.RemoveProgram("security"))
+	assert.Empty(t, s.Programs())
+
+	// Verify that Run stops the pollers and closes the channels once cancelled
+	cancel()
+	for range s.Errors() {
+	}
+	assert.Equal(t, context.Canceled, <-done)
+	_, ok := <-s.Reports()
+	assert.False(t, ok)
+	_, ok = <-s.Activities()
+	assert.False(t, ok)
+	assert.Equal(t, ErrSchedulerStopped, s.AddProgram("triage", "security", h1.ReportListFilter{}, opts))
+	assert.Equal(t, ErrAlreadyRunning, s.Run(context.Background()))
+}
+
+func Test_Scheduler_Shutdown(t *testing.T) {
+	f, c := newFakeProgram(t)
+	c.RateLimiter = &h1.RateLimiter{}
+	now := time.Now().UTC()
+	for _, id := range []string{"1", "2", "3"} {
+		f.setActivity("1", id, now)
+	}
+	f.setActivity("2", "4", now)
+	store := NewMemoryCheckpointStore()
+	s := NewScheduler(&SchedulerOptions{BufferSize: 2})
+	require.NoError(t, s.AddCredential("triage", c))
+	require.NoError(t, s.AddProgram("triage", "security", h1.ReportListFilter{}, &Options{Interval: 10 * time.Millisecond, Window: time.Hour, CheckpointStore: store}))
+
+	// Stop while the channels are full and an event is held back by the scheduler
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	received := map[string]bool{}
+	errs, reports, activities := s.Errors(), s.Reports(), s.Activities()
+	for errs != nil || reports != nil || activities != nil {
+		select {
+		case _, ok := <-errs:
+			if !ok {
+				errs = nil
+			}
+		case report, ok := <-reports:
+			if !ok {
+				reports = nil
+				continue
+			}
+			received[*report.Report.ID] = true
+		case activity, ok := <-activities:
+			if !ok {
+				activities = nil
+				continue
+			}
+			received[*activity.Activity.Report().ID+"/"+*activity.Activity.ID] = true
+		}
+	}
+	assert.Equal(t, context.Canceled, <-done)
+
+	// Verify that every checkpointed event was received
+	checkpoint, err := store.Load(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, checkpoint)
+	require.NotEmpty(t, checkpoint.Reports)
+	for reportID, progress := range checkpoint.Reports {
+		assert.True(t, received[reportID], reportID)
+		for _, activityID := range progress.ActivityIDs {
+			assert.True(t, received[reportID+"/"+activityID], reportID+"/"+activityID)
+		}
+	}
+	assert.True(t, received["1/3"])
+}